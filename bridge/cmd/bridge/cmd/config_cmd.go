@@ -19,6 +19,7 @@ func NewConfigCmd() *cobra.Command {
 	configCmd.AddCommand(newConfigPathCmd())
 	configCmd.AddCommand(newConfigValidateCmd())
 	configCmd.AddCommand(newConfigApplyCmd())
+	configCmd.AddCommand(newConfigRemoteCmd())
 	return configCmd
 }
 
@@ -39,7 +40,6 @@ func newConfigValidateCmd() *cobra.Command {
 		Use:   "validate",
 		Short: "Validate a config file",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			logger := logging.FromContext(cmd.Context())
 			cfg, err := config.Load(config.LoadOptions{
 				ConfigFile: firstNonEmpty(file, GetConfigFileFlag()),
 			})
@@ -49,7 +49,8 @@ func newConfigValidateCmd() *cobra.Command {
 			if err := cfg.Validate(); err != nil {
 				return err
 			}
-			logger.Info("config valid", "agent_id", cfg.Agent.ID, "server_url", cfg.Server.URL)
+			ctx := logging.WithAttrs(cmd.Context(), "agent_id", cfg.Agent.ID)
+			logging.FromContext(ctx).Info("config valid", "server_url", cfg.Server.URL)
 			fmt.Fprintln(os.Stdout, "ok")
 			return nil
 		},
@@ -64,7 +65,6 @@ func newConfigApplyCmd() *cobra.Command {
 		Use:   "apply",
 		Short: "Apply config file to default location",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			logger := logging.FromContext(cmd.Context())
 			src := firstNonEmpty(file, GetConfigFileFlag())
 			if src == "" {
 				return fmt.Errorf("missing --file (or --config)")
@@ -73,7 +73,7 @@ func newConfigApplyCmd() *cobra.Command {
 			if err := config.ApplyFile(src, dst); err != nil {
 				return err
 			}
-			logger.Info("config applied", "path", dst)
+			logging.FromContext(cmd.Context()).Info("config applied", "path", dst)
 			fmt.Fprintln(os.Stdout, dst)
 			return nil
 		},
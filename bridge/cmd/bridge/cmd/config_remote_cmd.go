@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newConfigRemoteCmd wraps the gateway's /api/admin/config HTTP surface so
+// operators can get/set/reload a running gateway's config without SSHing in.
+func newConfigRemoteCmd() *cobra.Command {
+	remoteCmd := &cobra.Command{
+		Use:   "remote",
+		Short: "Inspect or change a running gateway's config over the admin API",
+	}
+	remoteCmd.AddCommand(newConfigRemoteGetCmd())
+	remoteCmd.AddCommand(newConfigRemoteSetCmd())
+	remoteCmd.AddCommand(newConfigRemoteReloadCmd())
+	return remoteCmd
+}
+
+func newConfigRemoteGetCmd() *cobra.Command {
+	var gateway string
+	var token string
+	c := &cobra.Command{
+		Use:   "get",
+		Short: "Print the gateway's currently-loaded config",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gw, err := requireGatewayFlag(gateway)
+			if err != nil {
+				return err
+			}
+			body, err := doAdminConfigRequest(cmd, http.MethodGet, gw+"/api/admin/config", token, nil)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stdout, string(body))
+			return nil
+		},
+	}
+	c.Flags().StringVar(&gateway, "gateway", "", "gateway base URL, e.g. http://localhost:8088")
+	c.Flags().StringVar(&token, "admin-token", "", "bearer token for the admin API (optional)")
+	return c
+}
+
+func newConfigRemoteSetCmd() *cobra.Command {
+	var gateway string
+	var token string
+	var file string
+	c := &cobra.Command{
+		Use:   "set",
+		Short: "Upload and atomically apply a new config to the gateway",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gw, err := requireGatewayFlag(gateway)
+			if err != nil {
+				return err
+			}
+			src := firstNonEmpty(file, GetConfigFileFlag())
+			if src == "" {
+				return fmt.Errorf("missing --file (or --config)")
+			}
+			raw, err := os.ReadFile(src)
+			if err != nil {
+				return err
+			}
+			body, err := doAdminConfigRequest(cmd, http.MethodPut, gw+"/api/admin/config", token, raw)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stdout, string(body))
+			return nil
+		},
+	}
+	c.Flags().StringVar(&gateway, "gateway", "", "gateway base URL, e.g. http://localhost:8088")
+	c.Flags().StringVar(&token, "admin-token", "", "bearer token for the admin API (optional)")
+	c.Flags().StringVar(&file, "file", "", "config file to upload")
+	return c
+}
+
+func newConfigRemoteReloadCmd() *cobra.Command {
+	var gateway string
+	var token string
+	c := &cobra.Command{
+		Use:   "reload",
+		Short: "Ask the gateway to re-read its config file from disk",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gw, err := requireGatewayFlag(gateway)
+			if err != nil {
+				return err
+			}
+			body, err := doAdminConfigRequest(cmd, http.MethodPost, gw+"/api/admin/config/reload", token, nil)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stdout, string(body))
+			return nil
+		},
+	}
+	c.Flags().StringVar(&gateway, "gateway", "", "gateway base URL, e.g. http://localhost:8088")
+	c.Flags().StringVar(&token, "admin-token", "", "bearer token for the admin API (optional)")
+	return c
+}
+
+func requireGatewayFlag(gateway string) (string, error) {
+	if gateway == "" {
+		return "", fmt.Errorf("missing --gateway")
+	}
+	return gateway, nil
+}
+
+func doAdminConfigRequest(cmd *cobra.Command, method, url, token string, body []byte) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(cmd.Context(), method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("gateway returned %s: %s", resp.Status, string(respBody))
+	}
+	return respBody, nil
+}
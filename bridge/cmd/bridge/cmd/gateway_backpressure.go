@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"bridge/internal/protocol"
+)
+
+// defaultSubscriberBuffer bounds how many envelopes an SSE subscriber can
+// fall behind before TypeChunk envelopes start coalescing/dropping instead
+// of piling up unboundedly. Non-chunk envelopes are never dropped.
+const defaultSubscriberBuffer = 128
+
+// defaultAgentBuffer is defaultSubscriberBuffer's counterpart for the
+// gateway->agent direction: it bounds how far sendToAgent's caller can get
+// ahead of runAgentWriter's actual websocket writes before the same
+// coalesce/drop behavior kicks in.
+const defaultAgentBuffer = 128
+
+// subscriber is one handleEventsSSE client: events are pushed onto buf and
+// popped by the handler's write loop, which marshals them to SSE frames.
+type subscriber struct {
+	id  string
+	buf *outboundBuffer
+}
+
+func newSubscriber(id string) *subscriber {
+	return &subscriber{id: id, buf: newOutboundBuffer(defaultSubscriberBuffer)}
+}
+
+// outboundItem is one envelope waiting to be delivered, tagged with the
+// coalesce key it was enqueued under (empty for non-chunk envelopes, which
+// are never coalesced or dropped).
+type outboundItem struct {
+	env protocol.Envelope
+	key string
+}
+
+// outboundBuffer is a bounded FIFO of envelopes shared between a producer
+// (publishEvent) and a single consumer goroutine (the SSE write loop, or an
+// agent connection's writer goroutine). Once it holds maxItems envelopes
+// (of any type), a new chunk for the same (req_id, stream_id, channel) is merged
+// into the last queued chunk instead of growing the buffer; a new chunk for
+// a different key is dropped and counted instead of evicting older data.
+// Non-chunk envelopes (control/lifecycle events) always enqueue regardless
+// of the cap, since dropping those silently would be far more surprising
+// than a parked SSE connection catching up late.
+type outboundBuffer struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	items    []outboundItem
+	maxItems int
+	closed   bool
+}
+
+func newOutboundBuffer(maxItems int) *outboundBuffer {
+	b := &outboundBuffer{maxItems: maxItems}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// push enqueues env, coalescing into or dropping the pending chunk for its
+// key once the buffer is full. drops, agentID and subID (subID may be "")
+// are only used to attribute a drop; push itself never blocks.
+func (b *outboundBuffer) push(env protocol.Envelope, drops *dropStats, agentID, subID string) {
+	key := chunkCoalesceKey(env)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	if key != "" && len(b.items) >= b.maxItems {
+		if n := len(b.items); n > 0 && b.items[n-1].key == key {
+			if merged, ok := mergeChunkEnvelopes(b.items[n-1].env, env); ok {
+				b.items[n-1].env = merged
+				b.cond.Signal()
+				return
+			}
+		}
+	}
+
+	if key != "" && len(b.items) >= b.maxItems {
+		n, lines := chunkSize(env)
+		if drops != nil {
+			drops.record(agentID, env.ReqID, subID, n, lines)
+		}
+		return
+	}
+
+	b.items = append(b.items, outboundItem{env: env, key: key})
+	b.cond.Signal()
+}
+
+// pop blocks until an envelope is available or the buffer is closed, in
+// which case it returns (Envelope{}, false).
+func (b *outboundBuffer) pop() (protocol.Envelope, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for len(b.items) == 0 && !b.closed {
+		b.cond.Wait()
+	}
+	if len(b.items) == 0 {
+		return protocol.Envelope{}, false
+	}
+	item := b.items[0]
+	b.items = b.items[1:]
+	return item.env, true
+}
+
+func (b *outboundBuffer) close() {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	b.cond.Broadcast()
+}
+
+// chunkCoalesceKey returns the (req_id, stream_id, channel) key chunks are
+// merged under, or "" for anything that isn't a mergeable TypeChunk.
+func chunkCoalesceKey(env protocol.Envelope) string {
+	if env.Type != protocol.TypeChunk {
+		return ""
+	}
+	var p protocol.ChunkPayload
+	if err := json.Unmarshal(env.Payload, &p); err != nil {
+		return ""
+	}
+	return env.ReqID + "|" + p.StreamID + "|" + p.Channel
+}
+
+// mergeChunkEnvelopes appends b's data onto a's, summing their drop counters
+// and keeping a's (earlier) Offset. It refuses to merge once a is already
+// EOF, since that chunk is terminal for its stream.
+func mergeChunkEnvelopes(a, b protocol.Envelope) (protocol.Envelope, bool) {
+	var pa, pb protocol.ChunkPayload
+	if err := json.Unmarshal(a.Payload, &pa); err != nil {
+		return a, false
+	}
+	if err := json.Unmarshal(b.Payload, &pb); err != nil {
+		return a, false
+	}
+	if pa.EOF {
+		return a, false
+	}
+
+	merged := pa
+	merged.Data = pa.Data + pb.Data
+	merged.EOF = pb.EOF
+	merged.DroppedBytes += pb.DroppedBytes
+	merged.DroppedLines += pb.DroppedLines
+	// b is the later chunk: its Total/ContentType (often only set on the
+	// final EOF chunk) must win, not get silently dropped by the merge.
+	if pb.Total != 0 {
+		merged.Total = pb.Total
+	}
+	if pb.ContentType != "" {
+		merged.ContentType = pb.ContentType
+	}
+
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return a, false
+	}
+	out := b
+	out.Payload = raw
+	out.Ts = b.Ts
+	return out, true
+}
+
+// chunkSize reports the byte and line counts a dropped chunk represents, for
+// drop-stat accounting.
+func chunkSize(env protocol.Envelope) (n int64, lines int64) {
+	var p protocol.ChunkPayload
+	if err := json.Unmarshal(env.Payload, &p); err != nil {
+		return 0, 0
+	}
+	return int64(len(p.Data)), int64(bytes.Count([]byte(p.Data), []byte("\n"))) + 1
+}
+
+// dropStats accumulates dropped-chunk byte/line counts along three
+// dimensions (agent, req_id, subscriber) so /internal/bridge/metrics can
+// expose where backpressure is actually landing.
+type dropStats struct {
+	mu    sync.Mutex
+	byKey map[dropDim]*dropCounter
+}
+
+type dropDim struct {
+	dim string // "agent", "req_id", or "subscriber"
+	id  string
+}
+
+type dropCounter struct {
+	bytes int64
+	lines int64
+}
+
+func newDropStats() *dropStats {
+	return &dropStats{byKey: make(map[dropDim]*dropCounter)}
+}
+
+func (d *dropStats) record(agentID, reqID, subID string, n, lines int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.add(dropDim{"agent", agentID}, n, lines)
+	d.add(dropDim{"req_id", reqID}, n, lines)
+	if subID != "" {
+		d.add(dropDim{"subscriber", subID}, n, lines)
+	}
+}
+
+// clearReqID and clearSubscriber drop the accumulated counters for a
+// completed request or a disconnected SSE subscriber. Both dimensions are
+// keyed by short-lived identifiers (a req_id lasts one call, a subscriber
+// UUID one SSE connection), so without this the map
+// would grow one entry per request/connection for the life of the process.
+func (d *dropStats) clearReqID(reqID string) {
+	d.mu.Lock()
+	delete(d.byKey, dropDim{"req_id", reqID})
+	d.mu.Unlock()
+}
+
+func (d *dropStats) clearSubscriber(subID string) {
+	d.mu.Lock()
+	delete(d.byKey, dropDim{"subscriber", subID})
+	d.mu.Unlock()
+}
+
+func (d *dropStats) add(key dropDim, n, lines int64) {
+	if key.id == "" {
+		return
+	}
+	c := d.byKey[key]
+	if c == nil {
+		c = &dropCounter{}
+		d.byKey[key] = c
+	}
+	c.bytes += n
+	c.lines += lines
+}
+
+// renderPrometheus formats accumulated drop counts as Prometheus text
+// exposition format, sorted for stable output.
+func (d *dropStats) renderPrometheus() []byte {
+	d.mu.Lock()
+	type row struct {
+		dim   string
+		id    string
+		bytes int64
+		lines int64
+	}
+	rows := make([]row, 0, len(d.byKey))
+	for key, c := range d.byKey {
+		rows = append(rows, row{key.dim, key.id, c.bytes, c.lines})
+	}
+	d.mu.Unlock()
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].dim != rows[j].dim {
+			return rows[i].dim < rows[j].dim
+		}
+		return rows[i].id < rows[j].id
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("# HELP bridge_dropped_chunk_bytes_total Chunk bytes dropped by backpressure, by dimension.\n")
+	buf.WriteString("# TYPE bridge_dropped_chunk_bytes_total counter\n")
+	for _, r := range rows {
+		fmt.Fprintf(&buf, "bridge_dropped_chunk_bytes_total{%s=%q} %d\n", r.dim, r.id, r.bytes)
+	}
+	buf.WriteString("# HELP bridge_dropped_chunk_lines_total Chunk lines dropped by backpressure, by dimension.\n")
+	buf.WriteString("# TYPE bridge_dropped_chunk_lines_total counter\n")
+	for _, r := range rows {
+		fmt.Fprintf(&buf, "bridge_dropped_chunk_lines_total{%s=%q} %d\n", r.dim, r.id, r.lines)
+	}
+	return buf.Bytes()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
@@ -14,7 +15,9 @@ import (
 	"time"
 
 	"bridge/internal/logging"
+	"bridge/internal/policy"
 	"bridge/internal/protocol"
+	"bridge/internal/protocol/sign"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
@@ -22,12 +25,29 @@ import (
 	"nhooyr.io/websocket"
 )
 
+// preferredCodecs is the gateway's codec preference order, used to pick a
+// session codec from the set an agent advertises in HELLO.Codecs.
+var preferredCodecs = []string{protocol.CodecCBOR, protocol.CodecMsgpack, protocol.CodecJSON}
+
+// gatewaySupportedFeatures is the full feature set this gateway build
+// implements; the session's negotiated set is this intersected with what
+// the agent advertises in HELLO.
+var gatewaySupportedFeatures = []string{
+	protocol.FeatureCancel,
+	protocol.FeatureInvokeAck,
+	protocol.FeatureResultAck,
+	protocol.FeaturePingPong,
+	protocol.FeatureChunkedTools,
+	protocol.FeatureHMACV1,
+}
+
 func NewGatewayCmd() *cobra.Command {
 	gatewayCmd := &cobra.Command{
 		Use:   "gateway",
 		Short: "Tunnel gateway (runs in cloud)",
 	}
 	gatewayCmd.AddCommand(newGatewayServeCmd())
+	gatewayCmd.AddCommand(newGatewayKeysCmd())
 	return gatewayCmd
 }
 
@@ -39,6 +59,12 @@ func newGatewayServeCmd() *cobra.Command {
 	var redisURL string
 	var redisKeyPrefix string
 	var redisTTLSeconds int
+	var configFile string
+	var adminToken string
+	var grpcListen string
+	var policyFile string
+	var jwtSecret string
+	var agentKeysFile string
 
 	c := &cobra.Command{
 		Use:   "serve",
@@ -55,6 +81,12 @@ func newGatewayServeCmd() *cobra.Command {
 				RedisURL:       redisURL,
 				RedisKeyPrefix: redisKeyPrefix,
 				RedisTTL:       time.Duration(redisTTLSeconds) * time.Second,
+				ConfigFile:     configFile,
+				AdminToken:     adminToken,
+				GRPCListenAddr: grpcListen,
+				PolicyFile:     policyFile,
+				JWTSecret:      jwtSecret,
+				AgentKeysFile:  agentKeysFile,
 			})
 			return server.run(ctx)
 		},
@@ -66,6 +98,12 @@ func newGatewayServeCmd() *cobra.Command {
 	c.Flags().StringVar(&redisURL, "redis-url", "", "redis connection URL for HA routing (optional)")
 	c.Flags().StringVar(&redisKeyPrefix, "redis-key-prefix", "agent_online:", "redis key prefix for registry")
 	c.Flags().IntVar(&redisTTLSeconds, "redis-ttl-seconds", 30, "redis registry TTL seconds")
+	c.Flags().StringVar(&configFile, "config-file", "", "config file served by the admin API (optional)")
+	c.Flags().StringVar(&adminToken, "admin-token", "", "bearer token required by /api/admin/* (optional)")
+	c.Flags().StringVar(&grpcListen, "grpc-listen", "", "listen address for the BridgeInternal gRPC service (optional, disabled if empty)")
+	c.Flags().StringVar(&policyFile, "policy-file", "", "tool authorization policy file, YAML or JSON (optional, all calls allowed if empty)")
+	c.Flags().StringVar(&jwtSecret, "jwt-secret", "", "HS256 secret for verifying Authorization: Bearer JWTs used as policy caller identity (optional)")
+	c.Flags().StringVar(&agentKeysFile, "agent-keys-file", "", "per-agent envelope signing keys file, YAML (optional; envelope signatures aren't enforced if empty)")
 	return c
 }
 
@@ -77,6 +115,12 @@ type gatewayOptions struct {
 	RedisURL       string
 	RedisKeyPrefix string
 	RedisTTL       time.Duration
+	ConfigFile     string
+	AdminToken     string
+	GRPCListenAddr string
+	PolicyFile     string
+	JWTSecret      string
+	AgentKeysFile  string
 }
 
 type gatewayServer struct {
@@ -86,9 +130,25 @@ type gatewayServer struct {
 	agents map[string]*agentConn
 
 	subsMu sync.Mutex
-	subs   map[string]chan []byte
+	subs   map[string]*subscriber
 
 	redis *redis.Client
+
+	configMu  sync.RWMutex
+	configRaw []byte
+
+	pendingMu sync.RWMutex
+	pending   map[string]*pendingCall
+
+	drops *dropStats
+
+	policy *policy.Engine
+	caller policy.CallerIdentity
+
+	keys *sign.KeyStore
+
+	devicesMu sync.Mutex
+	devices   map[string]string // agent_id -> bound device fingerprint (TOFU)
 }
 
 type agentConn struct {
@@ -97,9 +157,13 @@ type agentConn struct {
 	connectedAt   time.Time
 	lastSeenAt    time.Time
 	conn          *websocket.Conn
+	codec         string
+	v             int
+	features      map[string]bool
 
-	writeMu sync.Mutex
-	tools   []protocol.ToolDescriptor
+	writeMu  sync.Mutex
+	tools    []protocol.ToolDescriptor
+	outbound *outboundBuffer
 }
 
 func newGatewayServer(opts gatewayOptions) *gatewayServer {
@@ -119,15 +183,29 @@ func newGatewayServer(opts gatewayOptions) *gatewayServer {
 		opts.RedisKeyPrefix = "agent_online:"
 	}
 	return &gatewayServer{
-		opts:   opts,
-		agents: make(map[string]*agentConn),
-		subs:   make(map[string]chan []byte),
+		opts:    opts,
+		agents:  make(map[string]*agentConn),
+		subs:    make(map[string]*subscriber),
+		pending: make(map[string]*pendingCall),
+		drops:   newDropStats(),
+		caller:  policy.NewCallerIdentity(opts.JWTSecret),
+		devices: make(map[string]string),
 	}
 }
 
 func (s *gatewayServer) run(ctx context.Context) error {
 	logger := logging.FromContext(ctx)
 
+	if strings.TrimSpace(s.opts.ConfigFile) != "" {
+		raw, err := os.ReadFile(s.opts.ConfigFile)
+		if err != nil {
+			return fmt.Errorf("read config file: %w", err)
+		}
+		s.configMu.Lock()
+		s.configRaw = raw
+		s.configMu.Unlock()
+	}
+
 	if strings.TrimSpace(s.opts.RedisURL) != "" {
 		client, err := newRedisClient(s.opts.RedisURL)
 		if err != nil {
@@ -135,7 +213,33 @@ func (s *gatewayServer) run(ctx context.Context) error {
 		}
 		s.redis = client
 		logger.Info("redis enabled for registry", "gateway_id", s.opts.GatewayID, "ttl_seconds", int(s.opts.RedisTTL.Seconds()))
+		s.rehydratePendingFromRedis(ctx)
 		go s.consumeCommandStream(ctx)
+		go s.consumePendingResultEvents(ctx)
+	}
+
+	go s.reapExpiredPending(ctx)
+
+	policyEngine, err := policy.NewEngine(s.opts.PolicyFile)
+	if err != nil {
+		return fmt.Errorf("load policy file: %w", err)
+	}
+	s.policy = policyEngine
+
+	keyStore, err := sign.NewKeyStore(s.opts.AgentKeysFile)
+	if err != nil {
+		return fmt.Errorf("load agent keys file: %w", err)
+	}
+	s.keys = keyStore
+
+	if strings.TrimSpace(s.opts.PolicyFile) != "" || strings.TrimSpace(s.opts.AgentKeysFile) != "" {
+		go s.reloadOnSIGHUP(ctx)
+	}
+
+	if strings.TrimSpace(s.opts.GRPCListenAddr) != "" {
+		if err := s.runGRPC(ctx); err != nil {
+			return fmt.Errorf("start grpc listener: %w", err)
+		}
 	}
 
 	mux := http.NewServeMux()
@@ -144,7 +248,11 @@ func (s *gatewayServer) run(ctx context.Context) error {
 	mux.HandleFunc("/internal/bridge/agents/", s.handleAgentSubresource)
 	mux.HandleFunc("/internal/bridge/invoke", s.handleInvoke)
 	mux.HandleFunc("/internal/bridge/cancel", s.handleCancel)
+	mux.HandleFunc("/internal/bridge/policy/simulate", s.handlePolicySimulate)
 	mux.HandleFunc("/internal/bridge/events", s.handleEventsSSE)
+	mux.HandleFunc("/api/admin/config", s.handleAdminConfig)
+	mux.HandleFunc("/api/admin/config/reload", s.handleAdminConfigReload)
+	mux.HandleFunc("/internal/bridge/metrics", s.handleMetrics)
 
 	httpServer := &http.Server{
 		Addr:              s.opts.ListenAddr,
@@ -160,7 +268,7 @@ func (s *gatewayServer) run(ctx context.Context) error {
 	}()
 
 	logger.Info("tunnel gateway listening", "addr", s.opts.ListenAddr, "tunnel_path", s.opts.TunnelPath)
-	err := httpServer.ListenAndServe()
+	err = httpServer.ListenAndServe()
 	if errors.Is(err, http.ErrServerClosed) {
 		return nil
 	}
@@ -186,8 +294,13 @@ func (s *gatewayServer) handleTunnelWS(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			break
 		}
-		env, err := protocol.DecodeEnvelope(data)
+		env, codecName, err := protocol.DecodeFrame(data)
 		if err != nil {
+			if _, ok := err.(protocol.ErrUnknownCodecTag); ok {
+				logger.Warn("unsupported codec tag, closing connection", "err", err.Error())
+				conn.Close(websocket.StatusUnsupportedData, err.Error())
+				break
+			}
 			logger.Warn("invalid envelope", "err", err.Error())
 			continue
 		}
@@ -196,26 +309,107 @@ func (s *gatewayServer) handleTunnelWS(w http.ResponseWriter, r *http.Request) {
 			env.AgentID = registered.agentID
 		}
 
+		var negotiatedFeatures map[string]bool
+		if registered != nil {
+			negotiatedFeatures = registered.features
+		}
+		if err := env.ValidateFeatures(negotiatedFeatures); err != nil {
+			logger.Warn("envelope uses unnegotiated feature, dropping", "type", env.Type, "agent_id", env.AgentID, "err", err.Error())
+			continue
+		}
+
+		if strings.TrimSpace(s.opts.AgentKeysFile) != "" {
+			if err := sign.Verify(env, s.keys.Lookup); err != nil {
+				logger.Warn("envelope signature verification failed", "type", env.Type, "agent_id", env.AgentID, "err", err.Error())
+				_ = s.sendToConn(ctx, conn, codecName, protocol.Envelope{
+					V:       protocol.MaxSupportedV,
+					Type:    protocol.TypeError,
+					AgentID: env.AgentID,
+					Ts:      time.Now().Unix(),
+					Payload: mustMarshalJSON(protocol.HandshakeErrorPayload{Reason: "signature_verification_failed"}),
+				})
+				conn.Close(websocket.StatusPolicyViolation, "signature_verification_failed")
+				break
+			}
+		}
+
 		switch env.Type {
 		case protocol.TypeHello:
 			if env.AgentID == "" {
 				logger.Warn("hello missing agent_id")
 				continue
 			}
+			var hello protocol.HelloPayload
+			_ = json.Unmarshal(env.Payload, &hello)
+			if hello.Auth != nil {
+				if err := s.checkDeviceBinding(ctx, env.AgentID, hello.Auth.DeviceFingerprint); err != nil {
+					logger.Warn("device fingerprint rejected, closing connection", "agent_id", env.AgentID, "err", err.Error())
+					_ = s.sendToConn(ctx, conn, codecName, protocol.Envelope{
+						V:       protocol.MaxSupportedV,
+						Type:    protocol.TypeError,
+						AgentID: env.AgentID,
+						Ts:      time.Now().Unix(),
+						Payload: mustMarshalJSON(protocol.HandshakeErrorPayload{Reason: "device_fingerprint_mismatch"}),
+					})
+					conn.Close(websocket.StatusPolicyViolation, "device_fingerprint_mismatch")
+					break
+				}
+			}
+			negotiatedCodec, ok := protocol.NegotiateCodec(preferredCodecs, hello.Codecs)
+			if !ok {
+				negotiatedCodec = codecName
+			}
+
+			peerMinV, peerMaxV := hello.MinV, hello.MaxV
+			if peerMinV <= 0 {
+				peerMinV = protocol.MinSupportedV
+			}
+			if peerMaxV <= 0 {
+				peerMaxV = protocol.MaxSupportedV
+			}
+			negotiatedV, versionOK := protocol.NegotiateVersion(protocol.MinSupportedV, protocol.MaxSupportedV, peerMinV, peerMaxV)
+			if !versionOK {
+				logger.Warn("hello version mismatch, closing connection", "agent_id", env.AgentID, "peer_min_v", peerMinV, "peer_max_v", peerMaxV)
+				_ = s.sendToConn(ctx, conn, codecName, protocol.Envelope{
+					V:       protocol.MaxSupportedV,
+					Type:    protocol.TypeError,
+					AgentID: env.AgentID,
+					Ts:      time.Now().Unix(),
+					Payload: mustMarshalJSON(protocol.HandshakeErrorPayload{
+						Reason: "version_mismatch",
+						Gap:    []string{fmt.Sprintf("gateway=[%d,%d]", protocol.MinSupportedV, protocol.MaxSupportedV), fmt.Sprintf("agent=[%d,%d]", peerMinV, peerMaxV)},
+					}),
+				})
+				conn.Close(websocket.StatusUnsupportedData, "version_mismatch")
+				break
+			}
+
+			negotiatedFeatures := protocol.IntersectFeatures(gatewaySupportedFeatures, hello.Features)
+			featureSet := make(map[string]bool, len(negotiatedFeatures))
+			for _, f := range negotiatedFeatures {
+				featureSet[f] = true
+			}
+
 			registered = &agentConn{
 				agentID:       env.AgentID,
 				connSessionID: firstNonEmpty(env.ConnSessionID, tmpSessionID),
 				connectedAt:   time.Now(),
 				lastSeenAt:    time.Now(),
 				conn:          conn,
+				codec:         negotiatedCodec,
+				v:             negotiatedV,
+				features:      featureSet,
+				outbound:      newOutboundBuffer(defaultAgentBuffer),
 			}
 			s.mu.Lock()
 			s.agents[env.AgentID] = registered
 			s.mu.Unlock()
+			go s.runAgentWriter(ctx, registered)
 			s.upsertRegistry(ctx, env.AgentID, registered.connSessionID)
-			logger.Info("agent registered", "agent_id", env.AgentID, "conn_session_id", registered.connSessionID)
+			logger.Info("agent registered", "agent_id", env.AgentID, "conn_session_id", registered.connSessionID, "codec", negotiatedCodec, "v", negotiatedV, "features", negotiatedFeatures)
+			s.resumeAgent(ctx, registered, hello.Resume)
 		case protocol.TypePing:
-			_ = s.sendToConn(ctx, conn, protocol.Envelope{
+			_ = s.sendToConn(ctx, conn, codecName, protocol.Envelope{
 				V:       1,
 				Type:    protocol.TypePong,
 				AgentID: env.AgentID,
@@ -245,8 +439,8 @@ func (s *gatewayServer) handleTunnelWS(w http.ResponseWriter, r *http.Request) {
 				s.mu.Unlock()
 				s.upsertRegistry(ctx, registered.agentID, registered.connSessionID)
 			}
-			if env.Type == protocol.TypeResult {
-				_ = s.sendToConn(ctx, conn, protocol.Envelope{
+			if env.Type == protocol.TypeResult && registered != nil && registered.features[protocol.FeatureResultAck] {
+				_ = s.sendToConn(ctx, conn, codecName, protocol.Envelope{
 					V:       1,
 					Type:    protocol.TypeResultAck,
 					AgentID: env.AgentID,
@@ -255,6 +449,9 @@ func (s *gatewayServer) handleTunnelWS(w http.ResponseWriter, r *http.Request) {
 					Payload: []byte("{}"),
 				})
 			}
+			if env.Type == protocol.TypeResult || env.Type == protocol.TypeCancelAck {
+				s.clearPending(ctx, env.ReqID)
+			}
 			s.publishEvent(env)
 		default:
 			s.publishEvent(env)
@@ -268,6 +465,7 @@ func (s *gatewayServer) handleTunnelWS(w http.ResponseWriter, r *http.Request) {
 			delete(s.agents, registered.agentID)
 		}
 		s.mu.Unlock()
+		registered.outbound.close()
 		s.deleteRegistryIfOwned(context.Background(), registered.agentID, registered.connSessionID)
 		s.publishEvent(&protocol.Envelope{
 			V:       1,
@@ -365,9 +563,14 @@ func (s *gatewayServer) handleInvoke(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	a := s.getAgent(req.AgentID)
-	if a == nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "agent_offline"})
+	caller, err := s.caller.Resolve(r.Header.Get("Authorization"))
+	if err != nil {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_caller_token"})
+		return
+	}
+	if decision := s.policy.Evaluate(policy.Request{Caller: caller, AgentID: req.AgentID, ToolName: req.ToolName, Args: req.Arguments}); !decision.Allowed {
+		s.auditPolicyDeny(caller, req.ToolName, decision)
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "forbidden_by_policy", "rule_id": decision.RuleID})
 		return
 	}
 
@@ -387,11 +590,78 @@ func (s *gatewayServer) handleInvoke(w http.ResponseWriter, r *http.Request) {
 		Ts:      time.Now().Unix(),
 		Payload: mustMarshalJSON(payload),
 	}
-	if err := s.sendToAgent(r.Context(), a, env); err != nil {
+
+	switch err := s.doInvoke(r.Context(), req.AgentID, req.ToolName, req.TimeoutMs, env); {
+	case errors.Is(err, errAgentOffline):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "agent_offline"})
+	case err != nil:
 		writeJSON(w, http.StatusBadGateway, map[string]string{"error": "send_failed"})
-		return
+	default:
+		writeJSON(w, http.StatusAccepted, map[string]string{"req_id": req.ReqID, "status": "accepted"})
 	}
-	writeJSON(w, http.StatusAccepted, map[string]string{"req_id": req.ReqID, "status": "accepted"})
+}
+
+// errAgentOffline and errSendFailed are the two failure modes doInvoke and
+// doCancel report; both the HTTP handlers and the gRPC Backend adapter
+// (internal/grpc via gatewayGRPCBackend) map these to their own error
+// shapes (a JSON body vs. a gRPC status).
+var (
+	errAgentOffline = errors.New("agent_offline")
+	errSendFailed   = errors.New("send_failed")
+)
+
+// doInvoke routes env to agentID — locally if connected to this gateway,
+// otherwise to its remote gateway over Redis (see forwardToGateway) — and
+// tracks it as pending so it can be resumed or reaped later. It's the
+// transport-agnostic core shared by handleInvoke (HTTP) and the gRPC
+// BridgeInternal.Invoke RPC.
+func (s *gatewayServer) doInvoke(ctx context.Context, agentID, toolName string, timeoutMs int, env protocol.Envelope) error {
+	a := s.getAgent(agentID)
+	remoteGatewayID := ""
+	if a == nil {
+		gatewayID, err := s.lookupRemoteGatewayID(ctx, agentID)
+		if err != nil {
+			return errAgentOffline
+		}
+		remoteGatewayID = gatewayID
+	}
+
+	deadline := time.Now().Add(invokeDeadline(timeoutMs))
+	s.storePending(ctx, pendingCall{
+		ReqID:        env.ReqID,
+		AgentID:      agentID,
+		ToolName:     toolName,
+		LastEnvelope: env,
+		DeadlineUnix: deadline.Unix(),
+	})
+
+	var sendErr error
+	if a != nil {
+		sendErr = s.sendToAgent(ctx, a, env)
+	} else {
+		sendErr = s.forwardToGateway(ctx, remoteGatewayID, env)
+	}
+	if sendErr != nil {
+		s.clearPending(ctx, env.ReqID)
+		return errSendFailed
+	}
+	return nil
+}
+
+// invokeDeadline returns how long a pending call should be kept around
+// before being reaped as INVOKE_FAILED, derived from the caller's
+// timeout_ms with a floor so very short timeouts still leave room for
+// reconnect-replay.
+func invokeDeadline(timeoutMs int) time.Duration {
+	const minDeadline = 30 * time.Second
+	if timeoutMs <= 0 {
+		return 5 * time.Minute
+	}
+	d := time.Duration(timeoutMs) * time.Millisecond
+	if d < minDeadline {
+		return minDeadline
+	}
+	return d
 }
 
 type cancelRequest struct {
@@ -417,12 +687,6 @@ func (s *gatewayServer) handleCancel(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "missing_fields"})
 		return
 	}
-	a := s.getAgent(req.AgentID)
-	if a == nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "agent_offline"})
-		return
-	}
-
 	env := protocol.Envelope{
 		V:       1,
 		Type:    protocol.TypeCancel,
@@ -431,11 +695,151 @@ func (s *gatewayServer) handleCancel(w http.ResponseWriter, r *http.Request) {
 		Ts:      time.Now().Unix(),
 		Payload: mustMarshalJSON(protocol.CancelPayload{Reason: req.Reason}),
 	}
-	if err := s.sendToAgent(r.Context(), a, env); err != nil {
+	switch err := s.doCancel(r.Context(), req.AgentID, env); {
+	case errors.Is(err, errAgentOffline):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "agent_offline"})
+	case err != nil:
 		writeJSON(w, http.StatusBadGateway, map[string]string{"error": "send_failed"})
+	default:
+		writeJSON(w, http.StatusAccepted, map[string]string{"req_id": req.ReqID, "status": "sent"})
+	}
+}
+
+// doCancel is the transport-agnostic core of handleCancel (HTTP) and the
+// gRPC BridgeInternal.Cancel RPC, routing env the same way doInvoke does
+// but without any pending-call bookkeeping.
+func (s *gatewayServer) doCancel(ctx context.Context, agentID string, env protocol.Envelope) error {
+	a := s.getAgent(agentID)
+	if a != nil {
+		if err := s.sendToAgent(ctx, a, env); err != nil {
+			return errSendFailed
+		}
+		return nil
+	}
+	gatewayID, err := s.lookupRemoteGatewayID(ctx, agentID)
+	if err != nil {
+		return errAgentOffline
+	}
+	if err := s.forwardToGateway(ctx, gatewayID, env); err != nil {
+		return errSendFailed
+	}
+	return nil
+}
+
+// handleAdminConfig serves the currently-loaded config (GET) and atomically
+// swaps in a new one (PUT), broadcasting TypeConfigReload to every
+// connected agent on a successful apply.
+func (s *gatewayServer) handleAdminConfig(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdminAuth(w, r) {
 		return
 	}
-	writeJSON(w, http.StatusAccepted, map[string]string{"req_id": req.ReqID, "status": "sent"})
+	switch r.Method {
+	case http.MethodGet:
+		s.configMu.RLock()
+		raw := s.configRaw
+		s.configMu.RUnlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if raw == nil {
+			raw = []byte("{}")
+		}
+		_, _ = w.Write(raw)
+	case http.MethodPut:
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "read_failed"})
+			return
+		}
+		if err := s.applyConfig(r.Context(), raw, "config.applied"); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "applied"})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAdminConfigReload re-reads the config file from disk (the path the
+// gateway was started with) and broadcasts TypeConfigReload.
+func (s *gatewayServer) handleAdminConfigReload(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdminAuth(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if strings.TrimSpace(s.opts.ConfigFile) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "no_config_file_configured"})
+		return
+	}
+	raw, err := os.ReadFile(s.opts.ConfigFile)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "read_failed"})
+		return
+	}
+	if err := s.applyConfig(r.Context(), raw, "config.reload"); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "reloaded"})
+}
+
+// applyConfig validates raw as JSON, swaps it in as the current config
+// (persisting to ConfigFile when configured), emits an audit log line under
+// auditEvent, and broadcasts TypeConfigReload to every connected agent.
+func (s *gatewayServer) applyConfig(ctx context.Context, raw []byte, auditEvent string) error {
+	var probe map[string]any
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return fmt.Errorf("invalid_config: %w", err)
+	}
+
+	if strings.TrimSpace(s.opts.ConfigFile) != "" {
+		if err := os.WriteFile(s.opts.ConfigFile, raw, 0o644); err != nil {
+			return fmt.Errorf("write_failed: %w", err)
+		}
+	}
+
+	s.configMu.Lock()
+	s.configRaw = raw
+	s.configMu.Unlock()
+
+	logging.FromContext(ctx).Info(auditEvent, "gateway_id", s.opts.GatewayID, "bytes", len(raw))
+	s.broadcastConfigReload(ctx, auditEvent)
+	return nil
+}
+
+func (s *gatewayServer) broadcastConfigReload(ctx context.Context, reason string) {
+	env := protocol.Envelope{
+		V:       1,
+		Type:    protocol.TypeConfigReload,
+		Ts:      time.Now().Unix(),
+		Payload: mustMarshalJSON(protocol.ConfigReloadPayload{Reason: reason}),
+	}
+	s.mu.RLock()
+	agents := make([]*agentConn, 0, len(s.agents))
+	for _, a := range s.agents {
+		agents = append(agents, a)
+	}
+	s.mu.RUnlock()
+	for _, a := range agents {
+		env.AgentID = a.agentID
+		_ = s.sendToAgent(ctx, a, env)
+	}
+}
+
+func (s *gatewayServer) checkAdminAuth(w http.ResponseWriter, r *http.Request) bool {
+	if s.opts.AdminToken == "" {
+		return true
+	}
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) || strings.TrimPrefix(header, prefix) != s.opts.AdminToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+	return true
 }
 
 func (s *gatewayServer) handleEventsSSE(w http.ResponseWriter, r *http.Request) {
@@ -452,76 +856,189 @@ func (s *gatewayServer) handleEventsSSE(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 
-	subID := uuid.NewString()
-	ch := make(chan []byte, 128)
-
-	s.subsMu.Lock()
-	s.subs[subID] = ch
-	s.subsMu.Unlock()
+	ctx := r.Context()
 
-	defer func() {
-		s.subsMu.Lock()
-		delete(s.subs, subID)
-		s.subsMu.Unlock()
+	// publishEvent always mirrors local events into Redis (see
+	// publishRedisEvent), so when Redis is configured we subscribe to its
+	// Pub/Sub channel instead of the local subs map: that single feed
+	// carries both this gateway's own events and ones produced on other
+	// gateway instances in the mesh, without delivering local events twice.
+	var sub *subscriber
+	var unsubscribe func()
+	if s.redis != nil {
+		sub, unsubscribe = s.subscribeRedisEvents(ctx, r.URL.Query().Get("agent_id"))
+	} else {
+		sub, unsubscribe = s.subscribeEvents()
+	}
+	defer unsubscribe()
+	go func() {
+		<-ctx.Done()
+		sub.buf.close()
 	}()
 
 	io.WriteString(w, "event: ready\ndata: {}\n\n")
 	flusher.Flush()
 
-	ctx := r.Context()
 	for {
-		select {
-		case <-ctx.Done():
+		env, ok := sub.buf.pop()
+		if !ok {
 			return
-		case msg := <-ch:
-			io.WriteString(w, "event: bridge\n")
-			io.WriteString(w, "data: ")
-			w.Write(msg)
-			io.WriteString(w, "\n\n")
-			flusher.Flush()
 		}
+		b, err := json.Marshal(env)
+		if err != nil {
+			continue
+		}
+		io.WriteString(w, "event: bridge\n")
+		io.WriteString(w, "data: ")
+		w.Write(b)
+		io.WriteString(w, "\n\n")
+		flusher.Flush()
 	}
 }
 
-func (s *gatewayServer) publishEvent(env *protocol.Envelope) {
-	b, err := json.Marshal(env)
-	if err != nil {
-		return
-	}
+// subscribeEvents registers a new local event subscriber — shared by
+// handleEventsSSE's local-subs path and the gRPC WatchEvents/Invoke
+// streams (via gatewayGRPCBackend) — and returns an unsubscribe func that
+// must be called exactly once when the caller is done consuming it.
+func (s *gatewayServer) subscribeEvents() (*subscriber, func()) {
+	sub := newSubscriber(uuid.NewString())
 	s.subsMu.Lock()
-	for _, ch := range s.subs {
-		select {
-		case ch <- b:
-		default:
+	s.subs[sub.id] = sub
+	s.subsMu.Unlock()
+	return sub, func() {
+		s.subsMu.Lock()
+		delete(s.subs, sub.id)
+		s.subsMu.Unlock()
+		sub.buf.close()
+		s.drops.clearSubscriber(sub.id)
+	}
+}
+
+// subscribeRedisEvents is subscribeEvents' counterpart for when Redis is
+// configured: instead of registering into the local subs map, it feeds a
+// subscriber's outboundBuffer from a Redis Pub/Sub channel, so an agent
+// invoked by a different gateway instance (see forwardToGateway) still
+// shows up here, and so cross-gateway fan-out gets the same coalescing/drop
+// accounting as local subscribers instead of a plain channel that silently
+// drops under backpressure. agentFilter narrows to one agent's channel, or
+// "" for the broad "bridge:evt" feed.
+func (s *gatewayServer) subscribeRedisEvents(ctx context.Context, agentFilter string) (*subscriber, func()) {
+	sub := newSubscriber(uuid.NewString())
+	channel := "bridge:evt"
+	if agentFilter != "" {
+		channel = "bridge:evt:agent:" + agentFilter
+	}
+	redisSub := s.redis.Subscribe(ctx, channel)
+	go func() {
+		for msg := range redisSub.Channel() {
+			var env protocol.Envelope
+			if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+				continue
+			}
+			sub.buf.push(env, s.drops, env.AgentID, sub.id)
 		}
+	}()
+	return sub, func() {
+		_ = redisSub.Close()
+		sub.buf.close()
+		s.drops.clearSubscriber(sub.id)
+	}
+}
+
+// publishEvent fans env out to every local SSE subscriber's bounded buffer
+// (which coalesces/drops TypeChunk envelopes under backpressure, see
+// outboundBuffer) and mirrors it into Redis for cross-gateway delivery.
+func (s *gatewayServer) publishEvent(env *protocol.Envelope) {
+	s.subsMu.Lock()
+	subs := make([]*subscriber, 0, len(s.subs))
+	for _, sub := range s.subs {
+		subs = append(subs, sub)
 	}
 	s.subsMu.Unlock()
+	for _, sub := range subs {
+		sub.buf.push(*env, s.drops, env.AgentID, sub.id)
+	}
 
+	b, err := json.Marshal(env)
+	if err != nil {
+		return
+	}
 	s.publishRedisEvent(env, b)
 }
 
+// handleMetrics exposes accumulated backpressure drop counts in Prometheus
+// text exposition format.
+func (s *gatewayServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if !s.checkInternalAuth(w, r) {
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(s.drops.renderPrometheus())
+}
+
 func (s *gatewayServer) getAgent(agentID string) *agentConn {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return s.agents[agentID]
 }
 
+// writeDeadline bounds how long a single websocket write may block. Each
+// agentConn has its own writeMu, so this only ever stalls the one agent it
+// belongs to; the deadline exists so a wedged TCP connection to that agent
+// fails fast instead of hanging whatever's waiting on the write (runAgentWriter
+// for the buffered path, or the HELLO/handshake errors that go out via
+// sendToConn before an agentConn even exists).
+const writeDeadline = 5 * time.Second
+
+// sendToAgent enqueues env on a's outbound buffer instead of writing to the
+// websocket directly, so a stuck agent connection only ever blocks its own
+// buffer (see runAgentWriter) and not the caller — doInvoke, doCancel,
+// resumeAgent's replay loop, broadcastConfigReload, or the Redis command-stream
+// consumer. Once full, TypeChunk envelopes coalesce/drop under dropStats'
+// "agent" dimension, the same backpressure strategy applied to SSE
+// subscribers (see outboundBuffer).
 func (s *gatewayServer) sendToAgent(ctx context.Context, a *agentConn, env protocol.Envelope) error {
+	a.outbound.push(env, s.drops, a.agentID, "")
+	return nil
+}
+
+// runAgentWriter pops envelopes off a's outbound buffer and performs the
+// actual websocket write, one at a time, until the buffer is closed (on
+// disconnect). It's started once per agentConn, alongside the connection's
+// read loop in handleTunnelWS.
+func (s *gatewayServer) runAgentWriter(ctx context.Context, a *agentConn) {
+	logger := logging.FromContext(ctx)
+	for {
+		env, ok := a.outbound.pop()
+		if !ok {
+			return
+		}
+		if err := s.writeToAgent(ctx, a, env); err != nil {
+			logger.Warn("agent write failed", "agent_id", a.agentID, "req_id", env.ReqID, "err", err.Error())
+		}
+	}
+}
+
+func (s *gatewayServer) writeToAgent(ctx context.Context, a *agentConn, env protocol.Envelope) error {
 	a.writeMu.Lock()
 	defer a.writeMu.Unlock()
-	data, err := protocol.EncodeEnvelope(env)
+	data, err := protocol.EncodeFrame(firstNonEmpty(a.codec, protocol.CodecJSON), env)
 	if err != nil {
 		return err
 	}
-	return a.conn.Write(ctx, websocket.MessageText, data)
+	wctx, cancel := context.WithTimeout(ctx, writeDeadline)
+	defer cancel()
+	return a.conn.Write(wctx, websocket.MessageBinary, data)
 }
 
-func (s *gatewayServer) sendToConn(ctx context.Context, conn *websocket.Conn, env protocol.Envelope) error {
-	data, err := protocol.EncodeEnvelope(env)
+func (s *gatewayServer) sendToConn(ctx context.Context, conn *websocket.Conn, codecName string, env protocol.Envelope) error {
+	data, err := protocol.EncodeFrame(firstNonEmpty(codecName, protocol.CodecJSON), env)
 	if err != nil {
 		return err
 	}
-	return conn.Write(ctx, websocket.MessageText, data)
+	wctx, cancel := context.WithTimeout(ctx, writeDeadline)
+	defer cancel()
+	return conn.Write(wctx, websocket.MessageBinary, data)
 }
 
 func (s *gatewayServer) checkInternalAuth(w http.ResponseWriter, r *http.Request) bool {
@@ -551,6 +1068,44 @@ func (s *gatewayServer) registryKey(agentID string) string {
 	return s.opts.RedisKeyPrefix + agentID
 }
 
+// lookupRemoteGatewayID returns the gateway_id registered for agentID in
+// Redis, for use when the agent isn't connected to this gateway instance.
+func (s *gatewayServer) lookupRemoteGatewayID(ctx context.Context, agentID string) (string, error) {
+	if s.redis == nil {
+		return "", errors.New("redis not configured")
+	}
+	raw, err := s.redis.Get(ctx, s.registryKey(agentID)).Bytes()
+	if err != nil {
+		return "", err
+	}
+	var val registryValue
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return "", err
+	}
+	if val.GatewayID == "" {
+		return "", errors.New("no gateway_id in registry entry")
+	}
+	return val.GatewayID, nil
+}
+
+// forwardToGateway hands env off to the gateway instance identified by
+// gatewayID via its Redis command stream, which that instance's
+// consumeCommandStream loop reads and delivers to its local agent
+// connection.
+func (s *gatewayServer) forwardToGateway(ctx context.Context, gatewayID string, env protocol.Envelope) error {
+	if s.redis == nil {
+		return errors.New("redis not configured")
+	}
+	raw, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	return s.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: "bridge:cmd:" + gatewayID,
+		Values: map[string]any{"envelope": raw},
+	}).Err()
+}
+
 func (s *gatewayServer) upsertRegistry(ctx context.Context, agentID string, connSessionID string) {
 	if s.redis == nil || agentID == "" {
 		return
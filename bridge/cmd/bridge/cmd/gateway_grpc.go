@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"net"
+
+	bridgegrpc "bridge/internal/grpc"
+	"bridge/internal/grpc/bridgepb"
+	"bridge/internal/logging"
+	"bridge/internal/protocol"
+
+	"google.golang.org/grpc"
+)
+
+// runGRPC starts the BridgeInternal gRPC listener alongside the HTTP+SSE
+// surface, sharing this gatewayServer's agents/subs state and Redis
+// registry via gatewayGRPCBackend. Like the HTTP listener, it's shut down
+// once ctx is done, from a goroutine started here.
+func (s *gatewayServer) runGRPC(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.opts.GRPCListenAddr)
+	if err != nil {
+		return err
+	}
+
+	grpcServer := grpc.NewServer()
+	bridgepb.RegisterBridgeInternalServer(grpcServer, bridgegrpc.NewServer(&gatewayGRPCBackend{s: s}))
+
+	logger := logging.FromContext(ctx)
+	go func() {
+		logger.Info("bridge internal grpc listening", "addr", s.opts.GRPCListenAddr)
+		if err := grpcServer.Serve(lis); err != nil {
+			logger.Warn("grpc server stopped", "err", err.Error())
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+	return nil
+}
+
+// gatewayGRPCBackend adapts gatewayServer to bridgegrpc.Backend, so the
+// BridgeInternal gRPC service reuses the exact same invoke/cancel routing,
+// pending-call tracking, and event fan-out as the HTTP+SSE surface instead
+// of a second, divergent implementation.
+type gatewayGRPCBackend struct {
+	s *gatewayServer
+}
+
+func (b *gatewayGRPCBackend) Invoke(ctx context.Context, env protocol.Envelope, toolName string, timeoutMs int) error {
+	return b.s.doInvoke(ctx, env.AgentID, toolName, timeoutMs, env)
+}
+
+func (b *gatewayGRPCBackend) Cancel(ctx context.Context, env protocol.Envelope) error {
+	return b.s.doCancel(ctx, env.AgentID, env)
+}
+
+func (b *gatewayGRPCBackend) ListAgents() []bridgegrpc.AgentSummary {
+	b.s.mu.RLock()
+	defer b.s.mu.RUnlock()
+	out := make([]bridgegrpc.AgentSummary, 0, len(b.s.agents))
+	for _, a := range b.s.agents {
+		out = append(out, bridgegrpc.AgentSummary{
+			AgentID:     a.agentID,
+			Status:      "online",
+			LastSeenAt:  a.lastSeenAt.Unix(),
+			ConnectedAt: a.connectedAt.Unix(),
+		})
+	}
+	return out
+}
+
+// Subscribe mirrors handleEventsSSE's choice between the local subs map and
+// Redis: when Redis is configured, an agent invoked through this gRPC
+// surface may actually be connected to a remote gateway, so its
+// CHUNK/RESULT only shows up here via Redis pub/sub, never through the
+// local subs map. subscribeRedisEvents carries the same outboundBuffer
+// coalescing/drop accounting as the local-subs path, rather than a plain
+// channel that silently drops under backpressure.
+func (b *gatewayGRPCBackend) Subscribe() bridgegrpc.EventSubscription {
+	if b.s.redis != nil {
+		sub, unsubscribe := b.s.subscribeRedisEvents(context.Background(), "")
+		return &grpcSubscription{sub: sub, unsubscribe: unsubscribe}
+	}
+	sub, unsubscribe := b.s.subscribeEvents()
+	return &grpcSubscription{sub: sub, unsubscribe: unsubscribe}
+}
+
+// grpcSubscription adapts the cmd package's internal *subscriber/outboundBuffer
+// type to bridgegrpc.EventSubscription.
+type grpcSubscription struct {
+	sub         *subscriber
+	unsubscribe func()
+}
+
+func (g *grpcSubscription) Next() (protocol.Envelope, bool) { return g.sub.buf.pop() }
+func (g *grpcSubscription) Close()                          { g.unsubscribe() }
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"bridge/internal/protocol/sign"
+
+	"github.com/spf13/cobra"
+)
+
+// newGatewayKeysCmd manages the --agent-keys-file a running gateway
+// verifies envelope signatures against. Edits take effect once the gateway
+// is sent SIGHUP (see gatewayServer.reloadOnSIGHUP).
+func newGatewayKeysCmd() *cobra.Command {
+	keysCmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Rotate or revoke per-agent envelope signing keys",
+	}
+	keysCmd.AddCommand(newGatewayKeysRotateCmd())
+	keysCmd.AddCommand(newGatewayKeysRevokeCmd())
+	return keysCmd
+}
+
+func newGatewayKeysRotateCmd() *cobra.Command {
+	var file string
+	var agentID string
+	var key string
+	c := &cobra.Command{
+		Use:   "rotate",
+		Short: "Set (or replace) an agent's signing key, generating a random one if --key is omitted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if agentID == "" {
+				return fmt.Errorf("missing --agent-id")
+			}
+			cfg, err := loadOrEmptyKeyConfig(file)
+			if err != nil {
+				return err
+			}
+			if key == "" {
+				key, err = randomKey()
+				if err != nil {
+					return err
+				}
+			}
+			if cfg.AgentKeys == nil {
+				cfg.AgentKeys = make(map[string]string)
+			}
+			cfg.AgentKeys[agentID] = key
+			if err := sign.SaveKeyConfig(file, cfg); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stdout, "rotated key for %s: %s\n", agentID, key)
+			return nil
+		},
+	}
+	c.Flags().StringVar(&file, "file", "", "agent keys file (the --agent-keys-file the gateway was started with)")
+	c.Flags().StringVar(&agentID, "agent-id", "", "agent to rotate the key for")
+	c.Flags().StringVar(&key, "key", "", "new key value (default: randomly generated)")
+	_ = c.MarkFlagRequired("file")
+	return c
+}
+
+func newGatewayKeysRevokeCmd() *cobra.Command {
+	var file string
+	var agentID string
+	c := &cobra.Command{
+		Use:   "revoke",
+		Short: "Remove an agent's per-agent key, falling back to the shared secret (if any)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if agentID == "" {
+				return fmt.Errorf("missing --agent-id")
+			}
+			cfg, err := loadOrEmptyKeyConfig(file)
+			if err != nil {
+				return err
+			}
+			delete(cfg.AgentKeys, agentID)
+			if err := sign.SaveKeyConfig(file, cfg); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stdout, "revoked key for %s\n", agentID)
+			return nil
+		},
+	}
+	c.Flags().StringVar(&file, "file", "", "agent keys file (the --agent-keys-file the gateway was started with)")
+	c.Flags().StringVar(&agentID, "agent-id", "", "agent to revoke the key for")
+	_ = c.MarkFlagRequired("file")
+	return c
+}
+
+func loadOrEmptyKeyConfig(path string) (sign.KeyConfig, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return sign.KeyConfig{}, nil
+	}
+	return sign.LoadKeyConfig(path)
+}
+
+func randomKey() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate key: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
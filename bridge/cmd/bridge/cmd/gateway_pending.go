@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"bridge/internal/logging"
+	"bridge/internal/protocol"
+)
+
+// pendingCallKeyPrefix namespaces the Redis keys used to persist in-flight
+// calls so a gateway restart doesn't lose track of them.
+const pendingCallKeyPrefix = "bridge:pending:"
+
+// pendingCall tracks one in-flight INVOKE so it can be replayed if the
+// agent reconnects having lost it, or synthesized into INVOKE_FAILED if it
+// times out with no RESULT.
+type pendingCall struct {
+	ReqID        string            `json:"req_id"`
+	AgentID      string            `json:"agent_id"`
+	ToolName     string            `json:"tool_name"`
+	LastEnvelope protocol.Envelope `json:"last_envelope"`
+	ChunkOffset  int64             `json:"chunk_offset"`
+	DeadlineUnix int64             `json:"deadline_unix"`
+}
+
+func (s *gatewayServer) storePending(ctx context.Context, call pendingCall) {
+	s.pendingMu.Lock()
+	if s.pending == nil {
+		s.pending = make(map[string]*pendingCall)
+	}
+	c := call
+	s.pending[call.ReqID] = &c
+	s.pendingMu.Unlock()
+
+	if s.redis == nil {
+		return
+	}
+	raw, err := json.Marshal(call)
+	if err != nil {
+		return
+	}
+	ttl := time.Until(time.Unix(call.DeadlineUnix, 0))
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	_ = s.redis.Set(ctx, pendingCallKeyPrefix+call.ReqID, raw, ttl).Err()
+}
+
+// rehydratePendingFromRedis scans bridge:pending:* at startup and loads any
+// surviving entries into s.pending. resumeAgent's replay and
+// reapExpiredPending's reaping both walk s.pending rather than Redis, so
+// without this a gateway restart would silently forget every call that was
+// pending before it came back up.
+func (s *gatewayServer) rehydratePendingFromRedis(ctx context.Context) {
+	if s.redis == nil {
+		return
+	}
+	logger := logging.FromContext(ctx)
+	loaded := 0
+	var cursor uint64
+	for {
+		keys, next, err := s.redis.Scan(ctx, cursor, pendingCallKeyPrefix+"*", 100).Result()
+		if err != nil {
+			logger.Warn("scan pending calls failed", "err", err.Error())
+			return
+		}
+		for _, key := range keys {
+			raw, err := s.redis.Get(ctx, key).Bytes()
+			if err != nil {
+				continue
+			}
+			var call pendingCall
+			if err := json.Unmarshal(raw, &call); err != nil {
+				continue
+			}
+			s.pendingMu.Lock()
+			if s.pending == nil {
+				s.pending = make(map[string]*pendingCall)
+			}
+			c := call
+			s.pending[call.ReqID] = &c
+			s.pendingMu.Unlock()
+			loaded++
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	if loaded > 0 {
+		logger.Info("rehydrated pending calls from redis", "count", loaded)
+	}
+}
+
+// getPending consults the in-memory map first, falling back to Redis by
+// req_id so a gateway that never called storePending for this call (e.g. the
+// agent's own gateway, after an invoke forwarded from elsewhere) or one that
+// just restarted still recognizes it as pending. A Redis hit is cached back
+// into the in-memory map.
+func (s *gatewayServer) getPending(ctx context.Context, reqID string) (*pendingCall, bool) {
+	s.pendingMu.RLock()
+	c, ok := s.pending[reqID]
+	s.pendingMu.RUnlock()
+	if ok {
+		return c, true
+	}
+	if s.redis == nil {
+		return nil, false
+	}
+	raw, err := s.redis.Get(ctx, pendingCallKeyPrefix+reqID).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	var call pendingCall
+	if err := json.Unmarshal(raw, &call); err != nil {
+		return nil, false
+	}
+	s.pendingMu.Lock()
+	if s.pending == nil {
+		s.pending = make(map[string]*pendingCall)
+	}
+	cached := call
+	s.pending[reqID] = &cached
+	s.pendingMu.Unlock()
+	return &cached, true
+}
+
+func (s *gatewayServer) clearPending(ctx context.Context, reqID string) {
+	s.pendingMu.Lock()
+	delete(s.pending, reqID)
+	s.pendingMu.Unlock()
+
+	if s.redis != nil {
+		_ = s.redis.Del(ctx, pendingCallKeyPrefix+reqID).Err()
+	}
+	s.drops.clearReqID(reqID)
+}
+
+// reapExpiredPending runs on a ticker from run(), synthesizing
+// INVOKE_FAILED for any pending call past its deadline with no RESULT.
+func (s *gatewayServer) reapExpiredPending(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reapExpiredPendingOnce(ctx)
+		}
+	}
+}
+
+func (s *gatewayServer) reapExpiredPendingOnce(ctx context.Context) {
+	now := time.Now().Unix()
+
+	s.pendingMu.Lock()
+	var expired []*pendingCall
+	for reqID, c := range s.pending {
+		if c.DeadlineUnix > 0 && c.DeadlineUnix < now {
+			expired = append(expired, c)
+			delete(s.pending, reqID)
+		}
+	}
+	s.pendingMu.Unlock()
+
+	logger := logging.FromContext(ctx)
+	for _, c := range expired {
+		if s.redis != nil {
+			_ = s.redis.Del(ctx, pendingCallKeyPrefix+c.ReqID).Err()
+		}
+		s.drops.clearReqID(c.ReqID)
+		logger.Warn("pending call expired", "req_id", c.ReqID, "agent_id", c.AgentID, "tool_name", c.ToolName)
+		s.publishEvent(&protocol.Envelope{
+			V:       1,
+			Type:    "INVOKE_FAILED",
+			AgentID: c.AgentID,
+			ReqID:   c.ReqID,
+			Ts:      time.Now().Unix(),
+			Payload: mustMarshalJSON(map[string]any{"error": "deadline_exceeded"}),
+		})
+	}
+}
+
+// consumePendingResultEvents subscribes to the cross-gateway event fan-out
+// and clears any pending call this instance is tracking once its RESULT or
+// CANCEL_ACK shows up. This is the counterpart to forwardToGateway: when an
+// INVOKE is forwarded to the agent's actual gateway, the pending entry is
+// still stored here (see doInvoke), but the agent's RESULT/CANCEL_ACK lands
+// on handleTunnelWS over there, not here, so this instance would otherwise
+// never clear it and reapExpiredPending would eventually synthesize a
+// spurious INVOKE_FAILED for a call that already succeeded.
+func (s *gatewayServer) consumePendingResultEvents(ctx context.Context) {
+	if s.redis == nil {
+		return
+	}
+	logger := logging.FromContext(ctx)
+	sub := s.redis.Subscribe(ctx, "bridge:evt")
+	defer sub.Close()
+	for msg := range sub.Channel() {
+		var env protocol.Envelope
+		if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+			continue
+		}
+		if env.Type != protocol.TypeResult && env.Type != protocol.TypeCancelAck {
+			continue
+		}
+		if _, ok := s.getPending(ctx, env.ReqID); !ok {
+			continue
+		}
+		s.clearPending(ctx, env.ReqID)
+		logger.Info("cleared pending call from remote gateway result", "req_id", env.ReqID, "agent_id", env.AgentID, "type", env.Type)
+	}
+}
+
+// resumeAgent replays any pending INVOKE the agent appears to have lost and
+// acks results the agent listed as pending_result_req_ids but the gateway
+// has already cleared (i.e. already received and ack'd), then emits a
+// RESUMED event.
+func (s *gatewayServer) resumeAgent(ctx context.Context, a *agentConn, resume *protocol.ResumePayload) {
+	logger := logging.FromContext(ctx)
+
+	s.pendingMu.RLock()
+	var toReplay []*pendingCall
+	for _, c := range s.pending {
+		if c.AgentID == a.agentID {
+			toReplay = append(toReplay, c)
+		}
+	}
+	s.pendingMu.RUnlock()
+
+	for _, c := range toReplay {
+		if err := s.sendToAgent(ctx, a, c.LastEnvelope); err != nil {
+			logger.Warn("resume replay failed", "req_id", c.ReqID, "agent_id", a.agentID, "err", err.Error())
+			continue
+		}
+		logger.Info("resumed pending invoke", "req_id", c.ReqID, "agent_id", a.agentID)
+	}
+
+	if resume != nil {
+		for _, reqID := range resume.PendingResultReqIDs {
+			if _, stillPending := s.getPending(ctx, reqID); stillPending {
+				continue
+			}
+			if !a.features[protocol.FeatureResultAck] {
+				continue
+			}
+			_ = s.sendToAgent(ctx, a, protocol.Envelope{
+				V:       1,
+				Type:    protocol.TypeResultAck,
+				AgentID: a.agentID,
+				ReqID:   reqID,
+				Ts:      time.Now().Unix(),
+				Payload: []byte("{}"),
+			})
+		}
+	}
+
+	s.publishEvent(&protocol.Envelope{
+		V:       1,
+		Type:    "RESUMED",
+		AgentID: a.agentID,
+		Ts:      time.Now().Unix(),
+	})
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"bridge/internal/policy"
+	"bridge/internal/protocol"
+)
+
+// auditPolicyDeny publishes a TypePolicyDeny event so a denied invoke shows
+// up in the /internal/bridge/events feed the same way an accepted one does
+// in its own RESULT/CHUNK events.
+func (s *gatewayServer) auditPolicyDeny(caller, toolName string, decision policy.Decision) {
+	s.publishEvent(&protocol.Envelope{
+		V:    1,
+		Type: protocol.TypePolicyDeny,
+		Ts:   time.Now().Unix(),
+		Payload: mustMarshalJSON(protocol.PolicyDenyPayload{
+			Caller:   caller,
+			ToolName: toolName,
+			RuleID:   decision.RuleID,
+			Reason:   decision.Reason,
+		}),
+	})
+}
+
+type policySimulateRequest struct {
+	Caller    string         `json:"caller"`
+	AgentID   string         `json:"agent_id"`
+	ToolName  string         `json:"tool_name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// handlePolicySimulate dry-runs a request against the currently-loaded
+// policy without sending anything to an agent, so operators can check a
+// rule change's effect before relying on it.
+func (s *gatewayServer) handlePolicySimulate(w http.ResponseWriter, r *http.Request) {
+	if !s.checkInternalAuth(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var req policySimulateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_json"})
+		return
+	}
+	if req.ToolName == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "missing_fields"})
+		return
+	}
+	decision := s.policy.Evaluate(policy.Request{
+		Caller:   req.Caller,
+		AgentID:  req.AgentID,
+		ToolName: req.ToolName,
+		Args:     req.Arguments,
+	})
+	writeJSON(w, http.StatusOK, map[string]any{
+		"allowed": decision.Allowed,
+		"rule_id": decision.RuleID,
+		"reason":  decision.Reason,
+	})
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"bridge/internal/logging"
+)
+
+// deviceBindingKeyPrefix namespaces the Redis keys used to persist each
+// agent's first-seen device fingerprint (TOFU), so the binding survives a
+// gateway restart instead of only living in the in-memory devices map.
+const deviceBindingKeyPrefix = "bridge:agent_device:"
+
+// reloadOnSIGHUP re-reads the policy and agent-keys files and swaps them
+// into s.policy/s.keys each time the process receives SIGHUP, so an
+// operator can change tool authorization rules or rotate/revoke signing
+// keys without restarting the gateway.  It returns once ctx is done.
+func (s *gatewayServer) reloadOnSIGHUP(ctx context.Context) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	logger := logging.FromContext(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			if strings.TrimSpace(s.opts.PolicyFile) != "" {
+				if err := s.policy.Reload(); err != nil {
+					logger.Warn("policy reload failed", "path", s.opts.PolicyFile, "err", err.Error())
+				} else {
+					logger.Info("policy reloaded", "path", s.opts.PolicyFile)
+				}
+			}
+			if strings.TrimSpace(s.opts.AgentKeysFile) != "" {
+				if err := s.keys.Reload(); err != nil {
+					logger.Warn("agent keys reload failed", "path", s.opts.AgentKeysFile, "err", err.Error())
+				} else {
+					logger.Info("agent keys reloaded", "path", s.opts.AgentKeysFile)
+				}
+			}
+		}
+	}
+}
+
+// checkDeviceBinding enforces trust-on-first-use: the first device
+// fingerprint seen for agentID is bound and remembered (in-memory, and in
+// Redis when configured, so it survives a restart); every later HELLO for
+// that agentID must present the same fingerprint. An empty fingerprint
+// isn't enforced (agents that don't send one aren't bound).
+func (s *gatewayServer) checkDeviceBinding(ctx context.Context, agentID, fingerprint string) error {
+	if fingerprint == "" {
+		return nil
+	}
+
+	s.devicesMu.Lock()
+	bound, ok := s.devices[agentID]
+	s.devicesMu.Unlock()
+
+	if !ok && s.redis != nil {
+		if val, err := s.redis.Get(ctx, deviceBindingKeyPrefix+agentID).Result(); err == nil {
+			bound, ok = val, true
+		}
+	}
+
+	if ok {
+		if bound != fingerprint {
+			return errors.New("device fingerprint does not match the one bound on first use")
+		}
+		return nil
+	}
+
+	s.devicesMu.Lock()
+	s.devices[agentID] = fingerprint
+	s.devicesMu.Unlock()
+	if s.redis != nil {
+		_ = s.redis.Set(ctx, deviceBindingKeyPrefix+agentID, fingerprint, 0).Err()
+	}
+	return nil
+}
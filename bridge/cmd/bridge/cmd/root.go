@@ -13,6 +13,7 @@ var (
 	globalConfigFile string
 	globalLogFormat  string
 	globalLogLevel   string
+	globalLogFile    string
 )
 
 func NewRootCmd() *cobra.Command {
@@ -23,8 +24,9 @@ func NewRootCmd() *cobra.Command {
 		SilenceErrors: true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			logger, err := logging.NewLogger(logging.Options{
-				Level:  globalLogLevel,
-				Format: globalLogFormat,
+				Level:   globalLogLevel,
+				Format:  globalLogFormat,
+				LogFile: globalLogFile,
 			})
 			if err != nil {
 				return err
@@ -41,7 +43,8 @@ func NewRootCmd() *cobra.Command {
 		"config file (default: search up for .ai-bridge/config.yaml, fallback: ~/.ai-bridge/config.yaml)",
 	)
 	rootCmd.PersistentFlags().StringVar(&globalLogFormat, "log-format", "text", "log format: text|json")
-	rootCmd.PersistentFlags().StringVar(&globalLogLevel, "log-level", "info", "log level: debug|info|warn|error")
+	rootCmd.PersistentFlags().StringVar(&globalLogLevel, "log-level", "info", "log level: trace|debug|info|warn|error|disabled")
+	rootCmd.PersistentFlags().StringVar(&globalLogFile, "log-file", "stderr", "log output: stdout|stderr|<path> (paths are rotated at 10MB, keeping 5 backups; not configurable from config.yaml)")
 
 	rootCmd.AddCommand(NewConfigCmd())
 	rootCmd.AddCommand(NewAgentCmd())
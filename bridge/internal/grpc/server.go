@@ -0,0 +1,195 @@
+// Package grpc implements the BridgeInternal gRPC service, a typed
+// alternative to the /internal/bridge/* HTTP+SSE surface for callers that
+// want streamed results and cancellation tied to the RPC's own lifetime
+// instead of polling HTTP with a bearer token per call.
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"bridge/internal/grpc/bridgepb"
+	"bridge/internal/protocol"
+)
+
+// AgentSummary is the subset of agent state ListAgents reports.
+type AgentSummary struct {
+	AgentID     string
+	Status      string
+	LastSeenAt  int64
+	ConnectedAt int64
+}
+
+// EventSubscription delivers published envelopes to one caller (an Invoke
+// or WatchEvents stream) until Close is called.
+type EventSubscription interface {
+	// Next blocks for the next envelope, returning ok=false once the
+	// subscription is closed (by Close, or by the backend on shutdown).
+	Next() (env protocol.Envelope, ok bool)
+	Close()
+}
+
+// Backend is the subset of gatewayServer this service needs, implemented
+// in cmd/bridge/cmd so that internal/grpc doesn't depend on the cobra
+// command package. Invoke/Cancel reuse the same routing (local agent vs.
+// remote gateway via Redis) and pending-call tracking as the HTTP surface.
+type Backend interface {
+	Invoke(ctx context.Context, env protocol.Envelope, toolName string, timeoutMs int) error
+	Cancel(ctx context.Context, env protocol.Envelope) error
+	ListAgents() []AgentSummary
+	Subscribe() EventSubscription
+}
+
+// Server implements bridgepb.BridgeInternalServer on top of a Backend.
+type Server struct {
+	bridgepb.UnimplementedBridgeInternalServer
+	backend Backend
+}
+
+func NewServer(backend Backend) *Server {
+	return &Server{backend: backend}
+}
+
+func (s *Server) Invoke(req *bridgepb.InvokeRequest, stream bridgepb.BridgeInternal_InvokeServer) error {
+	ctx := stream.Context()
+
+	var args map[string]any
+	if len(req.ArgumentsJson) > 0 {
+		_ = json.Unmarshal(req.ArgumentsJson, &args)
+	}
+	payload, err := json.Marshal(protocol.InvokePayload{
+		Tool:      protocol.ToolCall{Name: req.ToolName, Args: args},
+		TimeoutMs: int(req.TimeoutMs),
+		Stream:    protocol.StreamOptions{Enabled: req.Stream},
+	})
+	if err != nil {
+		return err
+	}
+	env := protocol.Envelope{
+		V:       1,
+		Type:    protocol.TypeInvoke,
+		AgentID: req.AgentId,
+		ReqID:   req.ReqId,
+		Payload: payload,
+	}
+
+	// Subscribe before sending the INVOKE: the agent may publish its
+	// CHUNK/RESULT envelopes fast enough that subscribing afterward would
+	// race and could miss them entirely.
+	sub := s.backend.Subscribe()
+	if err := s.backend.Invoke(ctx, env, req.ToolName, int(req.TimeoutMs)); err != nil {
+		sub.Close()
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// The caller hung up on the stream before the tool call
+			// finished: propagate that as a CANCEL to the agent instead of
+			// leaving it running with nobody listening for the result.
+			_ = s.backend.Cancel(context.Background(), protocol.Envelope{
+				V:       1,
+				Type:    protocol.TypeCancel,
+				AgentID: req.AgentId,
+				ReqID:   req.ReqId,
+				Payload: []byte(`{"reason":"client_disconnected"}`),
+			})
+			sub.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		env, ok := sub.Next()
+		if !ok {
+			return ctx.Err()
+		}
+		if env.ReqID != req.ReqId {
+			continue
+		}
+		if err := stream.Send(toProtoEnvelope(env)); err != nil {
+			sub.Close()
+			return err
+		}
+		if env.Type == protocol.TypeResult || env.Type == protocol.TypeCancelAck {
+			sub.Close()
+			return nil
+		}
+	}
+}
+
+func (s *Server) Cancel(ctx context.Context, req *bridgepb.CancelRequest) (*bridgepb.CancelResponse, error) {
+	env := protocol.Envelope{
+		V:       1,
+		Type:    protocol.TypeCancel,
+		AgentID: req.AgentId,
+		ReqID:   req.ReqId,
+		Payload: mustMarshalReason(req.Reason),
+	}
+	if err := s.backend.Cancel(ctx, env); err != nil {
+		return nil, err
+	}
+	return &bridgepb.CancelResponse{Status: "sent"}, nil
+}
+
+func (s *Server) ListAgents(ctx context.Context, req *bridgepb.ListAgentsRequest) (*bridgepb.ListAgentsResponse, error) {
+	agents := s.backend.ListAgents()
+	resp := &bridgepb.ListAgentsResponse{Agents: make([]*bridgepb.AgentInfo, 0, len(agents))}
+	for _, a := range agents {
+		resp.Agents = append(resp.Agents, &bridgepb.AgentInfo{
+			AgentId:     a.AgentID,
+			Status:      a.Status,
+			LastSeenAt:  a.LastSeenAt,
+			ConnectedAt: a.ConnectedAt,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) WatchEvents(req *bridgepb.WatchEventsRequest, stream bridgepb.BridgeInternal_WatchEventsServer) error {
+	ctx := stream.Context()
+	sub := s.backend.Subscribe()
+	go func() {
+		<-ctx.Done()
+		sub.Close()
+	}()
+
+	for {
+		env, ok := sub.Next()
+		if !ok {
+			return ctx.Err()
+		}
+		if req.AgentId != "" && env.AgentID != req.AgentId {
+			continue
+		}
+		if err := stream.Send(toProtoEnvelope(env)); err != nil {
+			sub.Close()
+			return err
+		}
+	}
+}
+
+func toProtoEnvelope(env protocol.Envelope) *bridgepb.Envelope {
+	return &bridgepb.Envelope{
+		V:             int32(env.V),
+		Type:          env.Type,
+		AgentId:       env.AgentID,
+		ReqId:         env.ReqID,
+		ConnSessionId: env.ConnSessionID,
+		Seq:           env.Seq,
+		Ts:            env.Ts,
+		Payload:       env.Payload,
+		Sig:           env.Sig,
+	}
+}
+
+func mustMarshalReason(reason string) []byte {
+	b, err := json.Marshal(protocol.CancelPayload{Reason: reason})
+	if err != nil {
+		return []byte("{}")
+	}
+	return b
+}
@@ -3,14 +3,42 @@ package logging
 import (
 	"context"
 	"errors"
+	"io"
 	"log/slog"
+	"math"
 	"os"
 	"strings"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// LevelTrace and LevelDisabled extend slog's level range so --log-level can
+// express "more verbose than debug" and "no logging at all".
+const (
+	LevelTrace    = slog.LevelDebug - 4
+	LevelDisabled = math.MaxInt
+)
+
+// Default rotation settings, used when the config does not override them.
+const (
+	DefaultRotateMaxSizeMB  = 10
+	DefaultRotateMaxBackups = 5
 )
 
 type Options struct {
 	Level  string
 	Format string
+
+	// LogFile is "stdout", "stderr", or a filesystem path. Empty means
+	// "stderr". The file (if any) is opened lazily and wrapped in a
+	// size-based rotator.
+	LogFile string
+
+	// RotateMaxSizeMB and RotateMaxBackups configure the rotator when
+	// LogFile is a path. Zero values fall back to the package defaults.
+	RotateMaxSizeMB  int
+	RotateMaxBackups int
+	RotateMaxAgeDays int
 }
 
 type Logger interface {
@@ -18,6 +46,8 @@ type Logger interface {
 	Info(msg string, args ...any)
 	Warn(msg string, args ...any)
 	Error(msg string, args ...any)
+	// With returns a Logger that prepends args to every subsequent log call.
+	With(args ...any) Logger
 }
 
 type SlogLogger struct {
@@ -29,15 +59,26 @@ func (l SlogLogger) Info(msg string, args ...any)  { l.logger.Info(msg, args...)
 func (l SlogLogger) Warn(msg string, args ...any)  { l.logger.Warn(msg, args...) }
 func (l SlogLogger) Error(msg string, args ...any) { l.logger.Error(msg, args...) }
 
+func (l SlogLogger) With(args ...any) Logger {
+	return SlogLogger{logger: l.logger.With(args...)}
+}
+
 func NewLogger(opts Options) (Logger, error) {
 	level := parseLevel(opts.Level)
-	var handler slog.Handler
 
+	sink, err := openSink(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level, AddSource: true}
+
+	var handler slog.Handler
 	switch strings.ToLower(strings.TrimSpace(opts.Format)) {
 	case "", "text":
-		handler = slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})
+		handler = slog.NewTextHandler(sink, handlerOpts)
 	case "json":
-		handler = slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level})
+		handler = slog.NewJSONHandler(sink, handlerOpts)
 	default:
 		return nil, errors.New("unsupported log format")
 	}
@@ -45,14 +86,47 @@ func NewLogger(opts Options) (Logger, error) {
 	return SlogLogger{logger: slog.New(handler)}, nil
 }
 
+// openSink resolves opts.LogFile to a writer. A filesystem path is wrapped
+// in a lumberjack rotator so long-running gateways and agents don't fill a
+// disk; "stdout"/"stderr"/"" map to the corresponding standard stream.
+func openSink(opts Options) (io.Writer, error) {
+	switch strings.ToLower(strings.TrimSpace(opts.LogFile)) {
+	case "", "stderr":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	default:
+		maxSize := opts.RotateMaxSizeMB
+		if maxSize <= 0 {
+			maxSize = DefaultRotateMaxSizeMB
+		}
+		maxBackups := opts.RotateMaxBackups
+		if maxBackups <= 0 {
+			maxBackups = DefaultRotateMaxBackups
+		}
+		return &lumberjack.Logger{
+			Filename:   opts.LogFile,
+			MaxSize:    maxSize,
+			MaxBackups: maxBackups,
+			MaxAge:     opts.RotateMaxAgeDays,
+		}, nil
+	}
+}
+
 func parseLevel(value string) slog.Level {
 	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "trace":
+		return LevelTrace
 	case "debug":
 		return slog.LevelDebug
+	case "info", "":
+		return slog.LevelInfo
 	case "warn", "warning":
 		return slog.LevelWarn
 	case "error":
 		return slog.LevelError
+	case "disabled":
+		return LevelDisabled
 	default:
 		return slog.LevelInfo
 	}
@@ -64,6 +138,8 @@ func WithLogger(ctx context.Context, logger Logger) context.Context {
 	return context.WithValue(ctx, ctxKey{}, logger)
 }
 
+// FromContext returns the Logger bound to ctx, or a stderr text logger if
+// none has been attached (e.g. in tests).
 func FromContext(ctx context.Context) Logger {
 	if ctx == nil {
 		return SlogLogger{logger: slog.New(slog.NewTextHandler(os.Stderr, nil))}
@@ -75,3 +151,14 @@ func FromContext(ctx context.Context) Logger {
 	}
 	return SlogLogger{logger: slog.New(slog.NewTextHandler(os.Stderr, nil))}
 }
+
+// WithAttrs replaces ctx's logger with one pre-bound to kv (alternating
+// key/value pairs, as accepted by slog). Conventionally used to bind
+// agent_id, req_id, and conn_session_id once per envelope so handlers don't
+// have to repeat them on every log call.
+func WithAttrs(ctx context.Context, kv ...any) context.Context {
+	if len(kv) == 0 {
+		return ctx
+	}
+	return WithLogger(ctx, FromContext(ctx).With(kv...))
+}
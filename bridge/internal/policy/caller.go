@@ -0,0 +1,106 @@
+package policy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CallerIdentity resolves the "caller" a Request is evaluated against from
+// an inbound HTTP request: the "sub" claim of a JWT in the Authorization
+// header if jwtSecret is configured, else "" (callers then only match
+// rules with an empty or "*" caller glob).
+type CallerIdentity struct {
+	jwtSecret []byte
+}
+
+func NewCallerIdentity(jwtSecret string) CallerIdentity {
+	return CallerIdentity{jwtSecret: []byte(jwtSecret)}
+}
+
+// Resolve extracts and verifies a bearer JWT from authHeader, returning its
+// "sub" claim. An empty authHeader or unconfigured secret both resolve to
+// ("", nil): the caller is simply anonymous, it's not an error by itself
+// (handleInvoke still requires the existing X-Internal-Token for transport
+// auth; this only adds a caller identity for policy matching).
+func (c CallerIdentity) Resolve(authHeader string) (string, error) {
+	if len(c.jwtSecret) == 0 || authHeader == "" {
+		return "", nil
+	}
+	const prefix = "Bearer "
+	if !strings.HasPrefix(authHeader, prefix) {
+		return "", errors.New("policy: Authorization header missing Bearer prefix")
+	}
+	claims, err := verifyHS256(strings.TrimPrefix(authHeader, prefix), c.jwtSecret)
+	if err != nil {
+		return "", err
+	}
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return "", errors.New("policy: jwt missing sub claim")
+	}
+	return sub, nil
+}
+
+// verifyHS256 checks an HS256-signed compact JWT (header.payload.signature)
+// against secret and returns its claims, rejecting tokens outside their
+// exp/nbf window. It's intentionally minimal: just enough claim handling
+// for caller identity, not a general-purpose JWT library.
+func verifyHS256(token string, secret []byte) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("policy: malformed jwt")
+	}
+	headerRaw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("policy: decode jwt header: %w", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(headerRaw, &header); err != nil {
+		return nil, fmt.Errorf("policy: decode jwt header: %w", err)
+	}
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("policy: unsupported jwt alg %q", header.Alg)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	wantSig := mac.Sum(nil)
+	gotSig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("policy: decode jwt signature: %w", err)
+	}
+	if !hmac.Equal(wantSig, gotSig) {
+		return nil, errors.New("policy: jwt signature mismatch")
+	}
+
+	payloadRaw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("policy: decode jwt payload: %w", err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payloadRaw, &claims); err != nil {
+		return nil, fmt.Errorf("policy: decode jwt payload: %w", err)
+	}
+
+	now := time.Now().Unix()
+	if exp, ok := numericClaim(claims, "exp"); ok && int64(exp) < now {
+		return nil, errors.New("policy: jwt expired")
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && int64(nbf) > now {
+		return nil, errors.New("policy: jwt not yet valid")
+	}
+	return claims, nil
+}
+
+func numericClaim(claims map[string]any, name string) (float64, bool) {
+	v, ok := claims[name].(float64)
+	return v, ok
+}
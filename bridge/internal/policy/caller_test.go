@@ -0,0 +1,47 @@
+package policy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func signHS256ForTest(t *testing.T, secret []byte, claims map[string]any) string {
+	t.Helper()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claimsRaw, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(claimsRaw)
+	signingInput := header + "." + payload
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	sig := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	return signingInput + "." + sig
+}
+
+func TestCallerIdentity_ResolveRoundTrip(t *testing.T) {
+	secret := []byte("jwt-secret")
+	c := NewCallerIdentity(string(secret))
+
+	sub, err := c.Resolve("")
+	if err != nil || sub != "" {
+		t.Fatalf("expected anonymous ok, got %q err=%v", sub, err)
+	}
+
+	token := signHS256ForTest(t, secret, map[string]any{"sub": "caller-1"})
+	sub, err = c.Resolve("Bearer " + token)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if sub != "caller-1" {
+		t.Fatalf("expected caller-1, got %q", sub)
+	}
+
+	if _, err := c.Resolve("Bearer " + token + "tampered"); err == nil {
+		t.Fatalf("expected tampered token to fail verification")
+	}
+}
@@ -0,0 +1,176 @@
+// Package policy implements tool-level authorization for the gateway's
+// /internal/bridge/invoke surface: a YAML/JSON rule set, keyed by caller
+// identity, agent_id glob, and tool name glob, that decides whether a given
+// (caller, agent, tool, arguments) tuple may be invoked.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Effect is the outcome a matching Rule produces.
+type Effect string
+
+const (
+	EffectAllow Effect = "allow"
+	EffectDeny  Effect = "deny"
+)
+
+// Rule is one entry in a Policy's rule list, evaluated in order; the first
+// rule whose globs all match wins.
+type Rule struct {
+	ID         string `yaml:"id" json:"id"`
+	Effect     Effect `yaml:"effect" json:"effect"`
+	CallerGlob string `yaml:"caller" json:"caller,omitempty"`
+	AgentGlob  string `yaml:"agent_id" json:"agent_id,omitempty"`
+	ToolGlob   string `yaml:"tool_name" json:"tool_name,omitempty"`
+	// ArgsSchema constrains InvokeRequest.Arguments when set: required keys
+	// and, for each, an expected JSON type ("string", "number", "bool",
+	// "array", "object"). See validateArgs for the (intentionally small)
+	// subset of JSON Schema this supports.
+	ArgsSchema map[string]ArgConstraint `yaml:"args_schema,omitempty" json:"args_schema,omitempty"`
+}
+
+// ArgConstraint is the per-argument slice of JSON Schema that Rule.ArgsSchema
+// enforces.
+type ArgConstraint struct {
+	Required bool     `yaml:"required,omitempty" json:"required,omitempty"`
+	Type     string   `yaml:"type,omitempty" json:"type,omitempty"`
+	Enum     []string `yaml:"enum,omitempty" json:"enum,omitempty"`
+}
+
+// Policy is the full rule set loaded from a file. DefaultEffect applies when
+// no rule matches.
+type Policy struct {
+	DefaultEffect Effect `yaml:"default_effect" json:"default_effect"`
+	Rules         []Rule `yaml:"rules" json:"rules"`
+}
+
+// Request is the tuple a policy Decision is evaluated against.
+type Request struct {
+	Caller   string
+	AgentID  string
+	ToolName string
+	Args     map[string]any
+}
+
+// Decision is the result of evaluating a Request against a Policy.
+type Decision struct {
+	Allowed bool
+	RuleID  string
+	Reason  string
+}
+
+// Engine holds a hot-swappable Policy, loaded from a file and reloaded in
+// place (see Reload) without disrupting in-flight evaluations.
+type Engine struct {
+	path    string
+	current atomic.Pointer[Policy]
+}
+
+// NewEngine loads path once and returns an Engine serving it. An empty path
+// yields an Engine with no policy loaded: Evaluate then always allows,
+// matching the gateway's behavior before this package existed.
+func NewEngine(path string) (*Engine, error) {
+	e := &Engine{path: path}
+	if strings.TrimSpace(path) == "" {
+		return e, nil
+	}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload re-reads the engine's file from disk and swaps it in atomically.
+// It's safe to call concurrently with Evaluate (e.g. from a SIGHUP handler).
+func (e *Engine) Reload() error {
+	if strings.TrimSpace(e.path) == "" {
+		return nil
+	}
+	raw, err := os.ReadFile(e.path)
+	if err != nil {
+		return fmt.Errorf("policy: read %s: %w", e.path, err)
+	}
+	pol, err := Parse(e.path, raw)
+	if err != nil {
+		return err
+	}
+	e.current.Store(pol)
+	return nil
+}
+
+// Parse decodes raw as a Policy, choosing YAML or JSON by name's extension
+// (defaulting to YAML for anything else, since that's the gateway config's
+// native format).
+func Parse(name string, raw []byte) (*Policy, error) {
+	var pol Policy
+	var err error
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		err = json.Unmarshal(raw, &pol)
+	} else {
+		err = yaml.Unmarshal(raw, &pol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("policy: parse %s: %w", name, err)
+	}
+	if pol.DefaultEffect == "" {
+		pol.DefaultEffect = EffectAllow
+	}
+	for i, r := range pol.Rules {
+		if r.ID == "" {
+			return nil, fmt.Errorf("policy: rule %d missing id", i)
+		}
+		if r.Effect != EffectAllow && r.Effect != EffectDeny {
+			return nil, fmt.Errorf("policy: rule %q has invalid effect %q", r.ID, r.Effect)
+		}
+	}
+	return &pol, nil
+}
+
+// Evaluate matches req against the engine's current rules in order,
+// returning the first match's Decision, or the policy's DefaultEffect if
+// none match. An Engine with no policy loaded always allows.
+func (e *Engine) Evaluate(req Request) Decision {
+	pol := e.current.Load()
+	if pol == nil {
+		return Decision{Allowed: true, Reason: "no_policy_loaded"}
+	}
+	for _, r := range pol.Rules {
+		if !globMatch(r.CallerGlob, req.Caller) {
+			continue
+		}
+		if !globMatch(r.AgentGlob, req.AgentID) {
+			continue
+		}
+		if !globMatch(r.ToolGlob, req.ToolName) {
+			continue
+		}
+		if len(r.ArgsSchema) > 0 && validateArgs(r.ArgsSchema, req.Args) != nil {
+			// Constraints not met: this rule doesn't match, fall through to
+			// the next one (or the default) rather than treating it as a
+			// denial in its own right.
+			continue
+		}
+		return Decision{Allowed: r.Effect == EffectAllow, RuleID: r.ID, Reason: string(r.Effect)}
+	}
+	return Decision{Allowed: pol.DefaultEffect == EffectAllow, Reason: "default_" + string(pol.DefaultEffect)}
+}
+
+// globMatch reports whether pattern matches s, where an empty pattern
+// matches anything and pattern syntax is path.Match's (*, ?, [...]).
+func globMatch(pattern, s string) bool {
+	if pattern == "" || pattern == "*" {
+		return true
+	}
+	ok, err := path.Match(pattern, s)
+	return err == nil && ok
+}
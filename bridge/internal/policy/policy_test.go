@@ -0,0 +1,77 @@
+package policy
+
+import "testing"
+
+func TestEvaluate_FirstMatchingRuleWins(t *testing.T) {
+	pol, err := Parse("policy.yaml", []byte(`
+default_effect: deny
+rules:
+  - id: deny-prod-shell
+    effect: deny
+    agent_id: "prod-*"
+    tool_name: "shell.*"
+  - id: allow-readonly
+    effect: allow
+    tool_name: "read_*"
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	e := &Engine{}
+	e.current.Store(pol)
+
+	deny := e.Evaluate(Request{AgentID: "prod-1", ToolName: "shell.exec"})
+	if deny.Allowed || deny.RuleID != "deny-prod-shell" {
+		t.Fatalf("expected deny-prod-shell, got %+v", deny)
+	}
+
+	allow := e.Evaluate(Request{AgentID: "staging-1", ToolName: "read_file"})
+	if !allow.Allowed || allow.RuleID != "allow-readonly" {
+		t.Fatalf("expected allow-readonly, got %+v", allow)
+	}
+
+	fallback := e.Evaluate(Request{AgentID: "staging-1", ToolName: "write_file"})
+	if fallback.Allowed {
+		t.Fatalf("expected default deny, got %+v", fallback)
+	}
+}
+
+func TestEvaluate_ArgsSchemaMismatchFallsThrough(t *testing.T) {
+	pol, err := Parse("policy.yaml", []byte(`
+default_effect: allow
+rules:
+  - id: deny-rm-with-path
+    effect: deny
+    tool_name: "rm"
+    args_schema:
+      path:
+        required: true
+        type: string
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	e := &Engine{}
+	e.current.Store(pol)
+
+	d := e.Evaluate(Request{ToolName: "rm", Args: map[string]any{}})
+	if !d.Allowed {
+		t.Fatalf("expected fallthrough to default allow when args_schema unmet, got %+v", d)
+	}
+
+	d = e.Evaluate(Request{ToolName: "rm", Args: map[string]any{"path": "/tmp/x"}})
+	if d.Allowed || d.RuleID != "deny-rm-with-path" {
+		t.Fatalf("expected deny-rm-with-path, got %+v", d)
+	}
+}
+
+func TestEngine_EmptyPathAlwaysAllows(t *testing.T) {
+	e, err := NewEngine("")
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	d := e.Evaluate(Request{ToolName: "anything"})
+	if !d.Allowed {
+		t.Fatalf("expected allow with no policy loaded, got %+v", d)
+	}
+}
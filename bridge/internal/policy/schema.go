@@ -0,0 +1,63 @@
+package policy
+
+import "fmt"
+
+// validateArgs checks args against schema: a required key that's absent, or
+// present with the wrong JSON type or outside its enum, fails. This is
+// deliberately a small subset of JSON Schema (type + required + enum on
+// top-level string/number/bool/array/object values) rather than a full
+// validator, since rules only need to gate obviously-wrong tool calls, not
+// replace ToolDescriptor.InputSchema's own validation on the agent side.
+func validateArgs(schema map[string]ArgConstraint, args map[string]any) error {
+	for name, constraint := range schema {
+		v, present := args[name]
+		if !present {
+			if constraint.Required {
+				return fmt.Errorf("policy: missing required argument %q", name)
+			}
+			continue
+		}
+		if constraint.Type != "" && !matchesJSONType(v, constraint.Type) {
+			return fmt.Errorf("policy: argument %q has wrong type, want %s", name, constraint.Type)
+		}
+		if len(constraint.Enum) > 0 && !inEnum(v, constraint.Enum) {
+			return fmt.Errorf("policy: argument %q not in allowed values", name)
+		}
+	}
+	return nil
+}
+
+func matchesJSONType(v any, want string) bool {
+	switch want {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "bool", "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	default:
+		return true
+	}
+}
+
+func inEnum(v any, enum []string) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	for _, e := range enum {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
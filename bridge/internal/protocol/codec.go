@@ -0,0 +1,79 @@
+package protocol
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Codec encodes and decodes a single Envelope to and from a specific wire
+// format. Implementations are registered by name and selected during the
+// HELLO handshake so agent and gateway can agree on a shared representation.
+type Codec interface {
+	// Name is the short codec identifier negotiated over HELLO (e.g. "json").
+	Name() string
+	Encode(env Envelope) ([]byte, error)
+	Decode(data []byte) (*Envelope, error)
+}
+
+var (
+	codecMu       sync.RWMutex
+	codecRegistry = map[string]Codec{}
+)
+
+// RegisterCodec adds c to the registry under c.Name(), overwriting any codec
+// previously registered under the same name.
+func RegisterCodec(c Codec) {
+	codecMu.Lock()
+	defer codecMu.Unlock()
+	codecRegistry[c.Name()] = c
+}
+
+// CodecByName returns the codec registered under name, if any.
+func CodecByName(name string) (Codec, bool) {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	c, ok := codecRegistry[name]
+	return c, ok
+}
+
+// RegisteredCodecNames returns the names of all registered codecs, used to
+// advertise support in HelloPayload.Codecs.
+func RegisteredCodecNames() []string {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	names := make([]string, 0, len(codecRegistry))
+	for name := range codecRegistry {
+		names = append(names, name)
+	}
+	return names
+}
+
+// NegotiateCodec returns the first name in preferred that is also present in
+// offered, or ("", false) if the two sides share no codec.
+func NegotiateCodec(preferred []string, offered []string) (string, bool) {
+	offeredSet := make(map[string]struct{}, len(offered))
+	for _, name := range offered {
+		offeredSet[name] = struct{}{}
+	}
+	for _, name := range preferred {
+		if _, ok := offeredSet[name]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func init() {
+	RegisterCodec(jsonCodec{})
+	RegisterCodec(cborCodec{})
+	RegisterCodec(msgpackCodec{})
+}
+
+// ErrUnknownCodecTag is returned by DecodeFrame when a frame's codec tag does
+// not match any registered codec. Callers should treat this as a
+// close-with-error condition.
+type ErrUnknownCodecTag byte
+
+func (e ErrUnknownCodecTag) Error() string {
+	return fmt.Sprintf("protocol: unknown codec tag 0x%02x", byte(e))
+}
@@ -0,0 +1,29 @@
+package protocol
+
+import "github.com/fxamacker/cbor/v2"
+
+// CodecCBOR is the codec name used for the CBOR wire format, preferred on
+// embedded agents where JSON parsing overhead matters.
+const CodecCBOR = "cbor"
+
+type cborCodec struct{}
+
+func (cborCodec) Name() string { return CodecCBOR }
+
+func (cborCodec) Encode(env Envelope) ([]byte, error) {
+	if err := env.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(env)
+}
+
+func (cborCodec) Decode(data []byte) (*Envelope, error) {
+	var env Envelope
+	if err := cbor.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if err := env.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
@@ -0,0 +1,16 @@
+package protocol
+
+// CodecJSON is the codec name used for the existing JSON wire format.
+const CodecJSON = "json"
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return CodecJSON }
+
+func (jsonCodec) Encode(env Envelope) ([]byte, error) {
+	return EncodeEnvelope(env)
+}
+
+func (jsonCodec) Decode(data []byte) (*Envelope, error) {
+	return DecodeEnvelope(data)
+}
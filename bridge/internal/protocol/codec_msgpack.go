@@ -0,0 +1,28 @@
+package protocol
+
+import "github.com/vmihailenco/msgpack/v5"
+
+// CodecMsgpack is the codec name used for the msgpack wire format.
+const CodecMsgpack = "msgpack"
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string { return CodecMsgpack }
+
+func (msgpackCodec) Encode(env Envelope) ([]byte, error) {
+	if err := env.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return msgpack.Marshal(env)
+}
+
+func (msgpackCodec) Decode(data []byte) (*Envelope, error) {
+	var env Envelope
+	if err := msgpack.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if err := env.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
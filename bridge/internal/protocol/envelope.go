@@ -3,6 +3,7 @@ package protocol
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -15,6 +16,13 @@ type Envelope struct {
 	Seq           int64           `json:"seq,omitempty"`
 	Ts            int64           `json:"ts,omitempty"`
 	Payload       json.RawMessage `json:"payload,omitempty"`
+	// Sig is a base64-encoded HMAC-SHA256 over the envelope's canonical
+	// fields, set by protocol/sign.Sign and checked by protocol/sign.Verify.
+	Sig string `json:"sig,omitempty"`
+	// Nonce is a sender-chosen unique value covered by Sig, used by
+	// protocol/sign.Verify to reject replays independent of Seq (e.g. for
+	// HELLO, which precedes any session to scope Seq by).
+	Nonce string `json:"nonce,omitempty"`
 }
 
 func (e Envelope) ValidateBasic() error {
@@ -24,10 +32,69 @@ func (e Envelope) ValidateBasic() error {
 	if strings.TrimSpace(e.Type) == "" {
 		return errors.New("invalid envelope: type is required")
 	}
+	if e.Type == TypeChunk && strings.TrimSpace(e.ReqID) == "" {
+		return errors.New("invalid envelope: CHUNK requires req_id")
+	}
+	return nil
+}
+
+// ValidateFeatures runs ValidateBasic and additionally rejects envelope
+// types that require a negotiated feature (see typeFeatureRequirement) not
+// present in negotiated. Pass a nil map when no session feature state is
+// available to the decoder (e.g. before HELLO completes) to skip this check.
+func (e Envelope) ValidateFeatures(negotiated map[string]bool) error {
+	if err := e.ValidateBasic(); err != nil {
+		return err
+	}
+	if negotiated == nil {
+		return nil
+	}
+	feature, ok := typeFeatureRequirement[e.Type]
+	if !ok {
+		return nil
+	}
+	if !negotiated[feature] {
+		return fmt.Errorf("invalid envelope: type %s requires unnegotiated feature %q", e.Type, feature)
+	}
 	return nil
 }
 
-func DecodeEnvelope(data []byte) (*Envelope, error) {
+// decodeConfig and encodeConfig hold the functional options collected by
+// DecodeEnvelope/EncodeEnvelope. They're deliberately defined here (rather
+// than taking a protocol/sign dependency) so this package has no knowledge
+// of the signing scheme; protocol/sign provides SignOption/VerifyOption,
+// which wrap its own Sign/Verify in WithSigner/WithVerifier.
+type decodeConfig struct {
+	verify func(*Envelope) error
+}
+
+type encodeConfig struct {
+	sign func(*Envelope) error
+}
+
+type DecodeOption func(*decodeConfig)
+
+type EncodeOption func(*encodeConfig)
+
+// WithVerifier runs verify against every envelope DecodeEnvelope decodes,
+// before returning it to the caller. Tests that don't care about signing
+// can simply omit this option.
+func WithVerifier(verify func(*Envelope) error) DecodeOption {
+	return func(c *decodeConfig) { c.verify = verify }
+}
+
+// WithSigner runs sign against an envelope immediately before
+// EncodeEnvelope marshals it, letting it populate Sig (or any other field).
+func WithSigner(sign func(*Envelope) error) EncodeOption {
+	return func(c *encodeConfig) { c.sign = sign }
+}
+
+func DecodeEnvelope(data []byte, opts ...DecodeOption) (*Envelope, error) {
+	var cfg decodeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	var env Envelope
 	if err := json.Unmarshal(data, &env); err != nil {
 		return nil, err
@@ -35,10 +102,25 @@ func DecodeEnvelope(data []byte) (*Envelope, error) {
 	if err := env.ValidateBasic(); err != nil {
 		return nil, err
 	}
+	if cfg.verify != nil {
+		if err := cfg.verify(&env); err != nil {
+			return nil, err
+		}
+	}
 	return &env, nil
 }
 
-func EncodeEnvelope(env Envelope) ([]byte, error) {
+func EncodeEnvelope(env Envelope, opts ...EncodeOption) ([]byte, error) {
+	var cfg encodeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.sign != nil {
+		if err := cfg.sign(&env); err != nil {
+			return nil, err
+		}
+	}
 	if err := env.ValidateBasic(); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,71 @@
+package protocol
+
+import "fmt"
+
+// Frame tags identify the codec used to encode the remainder of a wire
+// frame. They are prepended as a single byte so a receiver can decode
+// without sniffing the payload, which matters once non-self-describing
+// formats like msgpack are in play.
+const (
+	FrameTagJSON    byte = 0x01
+	FrameTagCBOR    byte = 0x02
+	FrameTagMsgpack byte = 0x03
+)
+
+var frameTagByCodec = map[string]byte{
+	CodecJSON:    FrameTagJSON,
+	CodecCBOR:    FrameTagCBOR,
+	CodecMsgpack: FrameTagMsgpack,
+}
+
+var codecByFrameTag = map[byte]string{
+	FrameTagJSON:    CodecJSON,
+	FrameTagCBOR:    CodecCBOR,
+	FrameTagMsgpack: CodecMsgpack,
+}
+
+// EncodeFrame encodes env with the named codec and prepends its one-byte
+// frame tag.
+func EncodeFrame(codecName string, env Envelope) ([]byte, error) {
+	tag, ok := frameTagByCodec[codecName]
+	if !ok {
+		return nil, fmt.Errorf("protocol: no frame tag registered for codec %q", codecName)
+	}
+	c, ok := CodecByName(codecName)
+	if !ok {
+		return nil, fmt.Errorf("protocol: codec %q not registered", codecName)
+	}
+	body, err := c.Encode(env)
+	if err != nil {
+		return nil, err
+	}
+	frame := make([]byte, 0, len(body)+1)
+	frame = append(frame, tag)
+	frame = append(frame, body...)
+	return frame, nil
+}
+
+// DecodeFrame reads the leading codec tag byte from data and decodes the
+// remainder with the matching codec. It returns ErrUnknownCodecTag if the
+// tag does not match any registered codec; callers should close the
+// connection with an error in that case rather than attempt to sniff the
+// payload.
+func DecodeFrame(data []byte) (*Envelope, string, error) {
+	if len(data) < 1 {
+		return nil, "", fmt.Errorf("protocol: empty frame")
+	}
+	tag := data[0]
+	codecName, ok := codecByFrameTag[tag]
+	if !ok {
+		return nil, "", ErrUnknownCodecTag(tag)
+	}
+	c, ok := CodecByName(codecName)
+	if !ok {
+		return nil, "", ErrUnknownCodecTag(tag)
+	}
+	env, err := c.Decode(data[1:])
+	if err != nil {
+		return nil, "", err
+	}
+	return env, codecName, nil
+}
@@ -3,6 +3,40 @@ package protocol
 type HelloPayload struct {
 	AgentMeta map[string]string `json:"agent_meta,omitempty"`
 	Resume    *ResumePayload    `json:"resume,omitempty"`
+	// Codecs lists the envelope codec names (see protocol.Codec) this side
+	// supports, in preference order. The receiving side intersects this
+	// with its own supported codecs to select one for the session.
+	Codecs []string `json:"codecs,omitempty"`
+
+	// MinV and MaxV are the envelope protocol version range this side
+	// supports. The peers negotiate the highest mutually-supported v.
+	MinV int `json:"min_v,omitempty"`
+	MaxV int `json:"max_v,omitempty"`
+	// Features lists optional protocol features this side supports (e.g.
+	// FeatureCancel, FeatureInvokeAck). The negotiated set is the
+	// intersection of both sides' lists.
+	Features []string `json:"features,omitempty"`
+	// MaxChunkBytes caps the size of a single TypeChunk payload this side
+	// is willing to receive.
+	MaxChunkBytes int `json:"max_chunk_bytes,omitempty"`
+	// Compression lists supported payload compression algorithms (e.g.
+	// "gzip", "zstd"). The gateway does not negotiate or apply it.
+	Compression []string `json:"compression,omitempty"`
+	// Auth carries the agent's token and device fingerprint. The gateway
+	// checks the fingerprint against its first-use binding (and, when agent
+	// keys are configured, the HELLO envelope's own Sig) before the agent is
+	// registered; it does not check Token.
+	Auth *AuthPayload `json:"auth,omitempty"`
+}
+
+// HandshakeErrorPayload accompanies the structured error envelope sent
+// before the gateway closes a connection: when a HELLO handshake finds no
+// mutually-supported version, or when a device fingerprint or envelope
+// signature is rejected. Gap describes what the two sides failed to agree
+// on (set for version mismatches only).
+type HandshakeErrorPayload struct {
+	Reason string   `json:"reason"`
+	Gap    []string `json:"gap,omitempty"`
 }
 
 type ResumePayload struct {
@@ -18,6 +52,15 @@ type ToolsPayload struct {
 	Tools []ToolDescriptor `json:"tools"`
 }
 
+// PolicyDenyPayload accompanies a TypePolicyDeny audit event published when
+// internal/policy denies an invoke request.
+type PolicyDenyPayload struct {
+	Caller   string `json:"caller,omitempty"`
+	ToolName string `json:"tool_name"`
+	RuleID   string `json:"rule_id,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
 type ToolDescriptor struct {
 	Name        string            `json:"name"`
 	Description string            `json:"description,omitempty"`
@@ -51,6 +94,23 @@ type ChunkPayload struct {
 	Data         string `json:"data"`
 	DroppedBytes int64  `json:"dropped_bytes,omitempty"`
 	DroppedLines int64  `json:"dropped_lines,omitempty"`
+
+	// Offset is the byte offset of Data within the stream, Total the
+	// stream's total byte length if known (0 if not yet known). EOF marks
+	// the final chunk. Together these let a receiver resume from Offset on
+	// reconnect instead of restarting the whole INVOKE.
+	Offset      int64  `json:"offset,omitempty"`
+	Total       int64  `json:"total,omitempty"`
+	EOF         bool   `json:"eof,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// ChunkAckPayload accompanies TypeChunkAck: the receiver's flow-control
+// credit grant, naming the highest contiguous offset it has received and
+// how many more bytes past that it is willing to buffer.
+type ChunkAckPayload struct {
+	Offset      int64 `json:"offset"`
+	WindowBytes int64 `json:"window_bytes"`
 }
 
 type ResultPayload struct {
@@ -75,3 +135,9 @@ type CancelAckPayload struct {
 	WillCancel bool   `json:"will_cancel"`
 	Reason     string `json:"reason,omitempty"`
 }
+
+// ConfigReloadPayload accompanies TypeConfigReload, sent by the gateway when
+// its config was applied or reloaded through the admin API.
+type ConfigReloadPayload struct {
+	Reason string `json:"reason,omitempty"`
+}
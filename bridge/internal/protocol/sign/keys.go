@@ -0,0 +1,107 @@
+package sign
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KeyConfig is the YAML format of the --agent-keys-file: a shared secret
+// used for every agent unless overridden by a per-agent entry. It is read
+// only from that file; config.yaml has no signing block.
+type KeyConfig struct {
+	SharedSecret string            `yaml:"shared_secret" json:"shared_secret,omitempty"`
+	AgentKeys    map[string]string `yaml:"agent_keys" json:"agent_keys,omitempty"`
+}
+
+// KeyLookup returns a keyLookup func (as accepted by Verify/VerifyOption)
+// backed by cfg: an agent-specific key if one is configured, else the
+// shared secret, else an error.
+func (cfg KeyConfig) KeyLookup() func(agentID string) ([]byte, error) {
+	return func(agentID string) ([]byte, error) {
+		if key, ok := cfg.AgentKeys[agentID]; ok && key != "" {
+			return []byte(key), nil
+		}
+		if cfg.SharedSecret != "" {
+			return []byte(cfg.SharedSecret), nil
+		}
+		return nil, fmt.Errorf("sign: no key configured for agent %q", agentID)
+	}
+}
+
+// LoadKeyConfig reads and YAML-decodes a KeyConfig from path (the file a
+// gateway is pointed at with --agent-keys-file, and what `bridge gateway
+// keys` rotate/revoke edit in place).
+func LoadKeyConfig(path string) (KeyConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return KeyConfig{}, fmt.Errorf("sign: read key config %s: %w", path, err)
+	}
+	var cfg KeyConfig
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return KeyConfig{}, fmt.Errorf("sign: parse key config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// SaveKeyConfig YAML-encodes cfg and writes it to path, overwriting any
+// existing content.
+func SaveKeyConfig(path string, cfg KeyConfig) error {
+	raw, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("sign: encode key config: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		return fmt.Errorf("sign: write key config %s: %w", path, err)
+	}
+	return nil
+}
+
+// KeyStore is a hot-reloadable KeyConfig, swapped in place (see Reload) so
+// a gateway can pick up `bridge gateway keys` edits without restarting.
+type KeyStore struct {
+	path    string
+	current atomic.Pointer[KeyConfig]
+}
+
+// NewKeyStore loads path once and returns a KeyStore serving it. An empty
+// path yields a KeyStore whose Lookup always errors, same as an unconfigured
+// KeyConfig.
+func NewKeyStore(path string) (*KeyStore, error) {
+	s := &KeyStore{path: path}
+	if path == "" {
+		empty := KeyConfig{}
+		s.current.Store(&empty)
+		return s, nil
+	}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload re-reads the store's file from disk and swaps it in atomically.
+// Safe to call concurrently with Lookup (e.g. from a SIGHUP handler).
+func (s *KeyStore) Reload() error {
+	if s.path == "" {
+		return nil
+	}
+	cfg, err := LoadKeyConfig(s.path)
+	if err != nil {
+		return err
+	}
+	s.current.Store(&cfg)
+	return nil
+}
+
+// Lookup is a keyLookup func (as accepted by Verify/VerifyOption) backed by
+// the store's current KeyConfig.
+func (s *KeyStore) Lookup(agentID string) ([]byte, error) {
+	cfg := s.current.Load()
+	if cfg == nil {
+		return nil, fmt.Errorf("sign: no key configured for agent %q", agentID)
+	}
+	return cfg.KeyLookup()(agentID)
+}
@@ -0,0 +1,49 @@
+package sign
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyStore_LoadAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-keys.yaml")
+	if err := SaveKeyConfig(path, KeyConfig{SharedSecret: "shared-1"}); err != nil {
+		t.Fatalf("SaveKeyConfig: %v", err)
+	}
+
+	store, err := NewKeyStore(path)
+	if err != nil {
+		t.Fatalf("NewKeyStore: %v", err)
+	}
+	key, err := store.Lookup("agent-1")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if string(key) != "shared-1" {
+		t.Fatalf("expected shared-1, got %q", key)
+	}
+
+	if err := SaveKeyConfig(path, KeyConfig{AgentKeys: map[string]string{"agent-1": "per-agent-1"}}); err != nil {
+		t.Fatalf("SaveKeyConfig rotate: %v", err)
+	}
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	key, err = store.Lookup("agent-1")
+	if err != nil {
+		t.Fatalf("Lookup after reload: %v", err)
+	}
+	if string(key) != "per-agent-1" {
+		t.Fatalf("expected per-agent-1 after reload, got %q", key)
+	}
+}
+
+func TestNewKeyStore_EmptyPathAlwaysErrors(t *testing.T) {
+	store, err := NewKeyStore("")
+	if err != nil {
+		t.Fatalf("NewKeyStore: %v", err)
+	}
+	if _, err := store.Lookup("agent-1"); err == nil {
+		t.Fatal("expected lookup with no key file configured to error")
+	}
+}
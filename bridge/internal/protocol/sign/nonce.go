@@ -0,0 +1,80 @@
+package sign
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// defaultNonceMaxEntries bounds the package-level nonce tracker the same
+// way defaultReplayMaxSessions bounds the seq-based one.
+const defaultNonceMaxEntries = 10000
+
+// nonceTracker rejects a previously-seen (agentID, nonce) pair, bounded to
+// the most recently seen entries and additionally evicting anything older
+// than 2x DefaultSkew, the window Verify uses (a replayed nonce outside that
+// window would already fail Verify's Ts check, so there's no need to
+// remember it longer). The eviction age does not follow the skew passed to
+// VerifyWithSkew.
+type nonceTracker struct {
+	mu      sync.Mutex
+	maxSize int
+	order   *list.List
+	seen    map[string]*list.Element
+}
+
+type nonceEntry struct {
+	key string
+	at  time.Time
+}
+
+func newNonceTracker(maxSize int) *nonceTracker {
+	return &nonceTracker{
+		maxSize: maxSize,
+		order:   list.New(),
+		seen:    make(map[string]*list.Element),
+	}
+}
+
+var defaultNonceTracker = newNonceTracker(defaultNonceMaxEntries)
+
+// Accept returns true the first time (agentID, nonce) is seen, recording
+// it; false on any repeat.
+func (t *nonceTracker) Accept(agentID, nonce string, now time.Time) bool {
+	key := agentID + "|" + nonce
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.evictOlderThan(now.Add(-2 * DefaultSkew))
+
+	if _, ok := t.seen[key]; ok {
+		return false
+	}
+	el := t.order.PushFront(&nonceEntry{key: key, at: now})
+	t.seen[key] = el
+	for t.order.Len() > t.maxSize {
+		oldest := t.order.Back()
+		if oldest == nil {
+			break
+		}
+		t.order.Remove(oldest)
+		delete(t.seen, oldest.Value.(*nonceEntry).key)
+	}
+	return true
+}
+
+func (t *nonceTracker) evictOlderThan(cutoff time.Time) {
+	for {
+		oldest := t.order.Back()
+		if oldest == nil {
+			return
+		}
+		entry := oldest.Value.(*nonceEntry)
+		if entry.at.After(cutoff) {
+			return
+		}
+		t.order.Remove(oldest)
+		delete(t.seen, entry.key)
+	}
+}
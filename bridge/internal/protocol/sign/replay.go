@@ -0,0 +1,79 @@
+package sign
+
+import (
+	"container/list"
+	"sync"
+)
+
+// defaultReplayMaxSessions bounds the number of conn_session_id entries the
+// package-level replay tracker keeps, evicting the least-recently-used
+// session once the bound is hit.
+const defaultReplayMaxSessions = 10000
+
+// replayTracker enforces that (agent_id, seq) is monotonically increasing
+// per conn_session_id, bounded to the most recently active sessions.
+type replayTracker struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List               // front = most recently used
+	entries    map[string]*list.Element // conn_session_id -> element
+}
+
+type replayEntry struct {
+	connSessionID  string
+	lastSeqByAgent map[string]int64
+}
+
+func newReplayTracker(maxEntries int) *replayTracker {
+	return &replayTracker{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+var defaultReplayTracker = newReplayTracker(defaultReplayMaxSessions)
+
+// Accept returns true if seq is greater than the last seq seen for
+// (connSessionID, agentID), recording it as the new high-water mark.
+// Sessions without a conn_session_id are not tracked (always accepted),
+// since there's nothing to key the LRU on.
+func (t *replayTracker) Accept(connSessionID, agentID string, seq int64) bool {
+	if connSessionID == "" {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	el, ok := t.entries[connSessionID]
+	var entry *replayEntry
+	if ok {
+		t.order.MoveToFront(el)
+		entry = el.Value.(*replayEntry)
+	} else {
+		entry = &replayEntry{connSessionID: connSessionID, lastSeqByAgent: map[string]int64{}}
+		el = t.order.PushFront(entry)
+		t.entries[connSessionID] = el
+		t.evictIfNeeded()
+	}
+
+	last, seen := entry.lastSeqByAgent[agentID]
+	if seen && seq <= last {
+		return false
+	}
+	entry.lastSeqByAgent[agentID] = seq
+	return true
+}
+
+func (t *replayTracker) evictIfNeeded() {
+	for t.order.Len() > t.maxEntries {
+		oldest := t.order.Back()
+		if oldest == nil {
+			return
+		}
+		entry := oldest.Value.(*replayEntry)
+		delete(t.entries, entry.connSessionID)
+		t.order.Remove(oldest)
+	}
+}
@@ -0,0 +1,123 @@
+// Package sign provides HMAC-SHA256 signing and verification of
+// protocol.Envelope values, plus replay protection for the (agent_id, seq)
+// pairs that flow through a signed session.
+package sign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bridge/internal/protocol"
+)
+
+// DefaultSkew is the maximum allowed difference between an envelope's Ts
+// and the verifier's clock.
+const DefaultSkew = 60 * time.Second
+
+// Canonical returns the canonical string HMAC'd over: v, type, agent_id,
+// req_id, conn_session_id, seq, ts, nonce, and sha256(payload), pipe-separated.
+func Canonical(env protocol.Envelope) string {
+	payloadSum := sha256.Sum256(env.Payload)
+	fields := []string{
+		strconv.Itoa(env.V),
+		env.Type,
+		env.AgentID,
+		env.ReqID,
+		env.ConnSessionID,
+		strconv.FormatInt(env.Seq, 10),
+		strconv.FormatInt(env.Ts, 10),
+		env.Nonce,
+		base64.StdEncoding.EncodeToString(payloadSum[:]),
+	}
+	return strings.Join(fields, "|")
+}
+
+// Sign computes an HMAC-SHA256 over Canonical(*env) with key and sets
+// env.Sig to the base64-encoded result.
+func Sign(env *protocol.Envelope, key []byte) error {
+	if len(key) == 0 {
+		return errors.New("sign: empty key")
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(Canonical(*env)))
+	env.Sig = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return nil
+}
+
+// Verify checks env.Sig against the HMAC computed with the key returned by
+// keyLookup for env.AgentID, enforces the Ts skew window, and rejects
+// replayed (agent_id, seq) pairs via the package-level replay tracker.
+func Verify(env *protocol.Envelope, keyLookup func(agentID string) ([]byte, error)) error {
+	return VerifyWithSkew(env, keyLookup, DefaultSkew)
+}
+
+// VerifyWithSkew is Verify with an explicit skew window, mainly so tests can
+// use a tighter or looser window than DefaultSkew.
+func VerifyWithSkew(env *protocol.Envelope, keyLookup func(agentID string) ([]byte, error), skew time.Duration) error {
+	if env.Sig == "" {
+		return errors.New("sign: missing sig")
+	}
+	sig, err := base64.StdEncoding.DecodeString(env.Sig)
+	if err != nil {
+		return fmt.Errorf("sign: invalid sig encoding: %w", err)
+	}
+	key, err := keyLookup(env.AgentID)
+	if err != nil {
+		return fmt.Errorf("sign: key lookup failed: %w", err)
+	}
+	if len(key) == 0 {
+		return errors.New("sign: empty key")
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(Canonical(*env)))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return errors.New("sign: signature mismatch")
+	}
+
+	now := time.Now()
+	ts := time.Unix(env.Ts, 0)
+	if skew > 0 {
+		delta := now.Sub(ts)
+		if delta < 0 {
+			delta = -delta
+		}
+		if delta > skew {
+			return fmt.Errorf("sign: ts %d outside %s skew window", env.Ts, skew)
+		}
+	}
+
+	if !defaultReplayTracker.Accept(env.ConnSessionID, env.AgentID, env.Seq) {
+		return fmt.Errorf("sign: replayed or out-of-order seq %d for agent %s", env.Seq, env.AgentID)
+	}
+
+	// Nonce-based replay protection covers what Seq can't: envelopes (like
+	// HELLO) sent before a conn_session_id exists to scope Seq by. Only
+	// enforced when the sender set one.
+	if env.Nonce != "" && !defaultNonceTracker.Accept(env.AgentID, env.Nonce, now) {
+		return fmt.Errorf("sign: replayed nonce %q for agent %s", env.Nonce, env.AgentID)
+	}
+	return nil
+}
+
+// SignOption returns a protocol.EncodeOption that signs an envelope with
+// key via Sign, for use with protocol.EncodeEnvelope(env, sign.SignOption(key)).
+func SignOption(key []byte) protocol.EncodeOption {
+	return protocol.WithSigner(func(env *protocol.Envelope) error {
+		return Sign(env, key)
+	})
+}
+
+// VerifyOption returns a protocol.DecodeOption that verifies an envelope via
+// Verify, for use with protocol.DecodeEnvelope(data, sign.VerifyOption(lookup)).
+func VerifyOption(keyLookup func(agentID string) ([]byte, error)) protocol.DecodeOption {
+	return protocol.WithVerifier(func(env *protocol.Envelope) error {
+		return Verify(env, keyLookup)
+	})
+}
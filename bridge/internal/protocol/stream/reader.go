@@ -0,0 +1,89 @@
+package stream
+
+import (
+	"encoding/json"
+
+	"bridge/internal/protocol"
+)
+
+// AckFunc sends a TypeChunkAck envelope back to the peer.
+type AckFunc func(protocol.ChunkAckPayload) error
+
+// Reader consumes TypeChunk envelopes from recvCh, tracks the highest
+// contiguous offset received, and periodically acks it with fresh window
+// credit so the sender's Writer can keep streaming. On reconnect, callers
+// should replay the last ack (via Offset()) so the sender resumes instead
+// of restarting the whole INVOKE.
+type Reader struct {
+	recvCh      <-chan protocol.Envelope
+	ack         AckFunc
+	windowBytes int64
+	metrics     Metrics
+
+	offset int64
+}
+
+type ReaderOption func(*Reader)
+
+func WithReaderWindowBytes(n int64) ReaderOption {
+	return func(r *Reader) {
+		if n > 0 {
+			r.windowBytes = n
+		}
+	}
+}
+
+func WithReaderMetrics(m Metrics) ReaderOption {
+	return func(r *Reader) { r.metrics = m }
+}
+
+// WithReaderResumeOffset seeds the reader's contiguous offset from the last
+// ack sent before a reconnect.
+func WithReaderResumeOffset(offset int64) ReaderOption {
+	return func(r *Reader) { r.offset = offset }
+}
+
+func NewReader(recvCh <-chan protocol.Envelope, ack AckFunc, opts ...ReaderOption) *Reader {
+	r := &Reader{
+		recvCh:      recvCh,
+		ack:         ack,
+		windowBytes: DefaultWindowBytes,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Offset returns the highest contiguous offset received so far, for use in
+// WithWriterResumeOffset/WithReaderResumeOffset after a reconnect.
+func (r *Reader) Offset() int64 { return r.offset }
+
+// Next blocks for the next TypeChunk envelope on recvCh, decodes its
+// payload, advances the contiguous offset, and sends an ack. It returns
+// (nil, false, nil) if recvCh is closed.
+func (r *Reader) Next() (*protocol.ChunkPayload, bool, error) {
+	env, ok := <-r.recvCh
+	if !ok {
+		return nil, false, nil
+	}
+	var payload protocol.ChunkPayload
+	if err := json.Unmarshal(env.Payload, &payload); err != nil {
+		return nil, true, err
+	}
+
+	// Only advance when the chunk starts at or before the current offset,
+	// so a gap leaves the ack at the highest contiguous byte received.
+	if end := payload.Offset + int64(len(payload.Data)); payload.Offset <= r.offset && end > r.offset {
+		r.offset = end
+	}
+
+	if r.ack != nil {
+		_ = r.ack(protocol.ChunkAckPayload{
+			Offset:      r.offset,
+			WindowBytes: r.windowBytes,
+		})
+		r.metrics.chunkAcked(r.offset)
+	}
+	return &payload, true, nil
+}
@@ -0,0 +1,36 @@
+// Package stream implements ACK-windowed flow control for protocol.TypeChunk
+// streams: a Writer that won't exceed WindowBytes of un-acked data in
+// flight (except for a single chunk larger than the window), and a Reader that periodically acks the highest contiguous offset
+// it has received so the sender can resume after a reconnect.
+package stream
+
+import "bridge/internal/protocol"
+
+// DefaultWindowBytes is the flow-control credit granted when no explicit
+// window is configured.
+const DefaultWindowBytes = 1 << 20 // 1MiB
+
+// Metrics are optional hooks a caller can wire to Prometheus counters.
+type Metrics struct {
+	// OnChunkSent is called after a chunk is handed to SendFunc, with its
+	// byte length.
+	OnChunkSent func(bytes int)
+	// OnChunkAcked is called for every ack a Reader sends or a Writer
+	// receives, with the ack's offset.
+	OnChunkAcked func(offset int64)
+}
+
+func (m Metrics) chunkSent(n int) {
+	if m.OnChunkSent != nil {
+		m.OnChunkSent(n)
+	}
+}
+
+func (m Metrics) chunkAcked(offset int64) {
+	if m.OnChunkAcked != nil {
+		m.OnChunkAcked(offset)
+	}
+}
+
+// SendFunc sends one envelope to the peer (e.g. gatewayServer.sendToAgent).
+type SendFunc func(protocol.Envelope) error
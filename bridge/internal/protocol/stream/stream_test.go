@@ -0,0 +1,89 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"bridge/internal/protocol"
+)
+
+func TestWriterReader_RoundTrip(t *testing.T) {
+	recvCh := make(chan protocol.Envelope, 4)
+	acks := make(chan protocol.ChunkAckPayload, 4)
+
+	w := NewWriter(func(env protocol.Envelope) error {
+		recvCh <- env
+		return nil
+	}, "agent-1", "req-1", WithWriterWindowBytes(1024))
+
+	r := NewReader(recvCh, func(ack protocol.ChunkAckPayload) error {
+		acks <- ack
+		w.HandleAck(ack)
+		return nil
+	})
+
+	if err := w.Write([]byte("hello"), "stdout", false); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	chunk, ok, err := r.Next()
+	if err != nil || !ok {
+		t.Fatalf("Next: ok=%v err=%v", ok, err)
+	}
+	if chunk.Data != "hello" || chunk.Offset != 0 {
+		t.Fatalf("unexpected chunk: %+v", chunk)
+	}
+
+	ack := <-acks
+	if ack.Offset != int64(len("hello")) {
+		t.Fatalf("expected ack offset %d, got %d", len("hello"), ack.Offset)
+	}
+}
+
+func TestWriter_BlocksUntilAcked(t *testing.T) {
+	recvCh := make(chan protocol.Envelope, 4)
+	w := NewWriter(func(env protocol.Envelope) error {
+		recvCh <- env
+		return nil
+	}, "agent-1", "req-1", WithWriterWindowBytes(4))
+
+	if err := w.Write([]byte("abcd"), "stdout", false); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_ = w.Write([]byte("e"), "stdout", false)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Write should have blocked on a full window")
+	default:
+	}
+
+	w.HandleAck(protocol.ChunkAckPayload{Offset: 4, WindowBytes: 4})
+	<-done
+}
+
+func TestWriter_OversizedChunkWithEmptyWindowDoesNotBlock(t *testing.T) {
+	recvCh := make(chan protocol.Envelope, 1)
+	w := NewWriter(func(env protocol.Envelope) error {
+		recvCh <- env
+		return nil
+	}, "agent-1", "req-1", WithWriterWindowBytes(4))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Write([]byte("abcdefgh"), "stdout", false)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Write deadlocked on a chunk larger than the window with nothing in flight")
+	}
+}
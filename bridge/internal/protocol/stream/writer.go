@@ -0,0 +1,136 @@
+package stream
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"bridge/internal/protocol"
+)
+
+// Writer sends TypeChunk envelopes for a single req_id, blocking Write calls
+// that would take unacked data past WindowBytes (unless nothing is in
+// flight, see Write) so a slow receiver applies
+// backpressure to the producer instead of the sender buffering unboundedly.
+type Writer struct {
+	send        SendFunc
+	agentID     string
+	reqID       string
+	windowBytes int64
+	metrics     Metrics
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	offset int64 // bytes sent so far
+	acked  int64 // highest contiguous offset acked by the receiver
+	closed bool
+}
+
+type WriterOption func(*Writer)
+
+func WithWriterWindowBytes(n int64) WriterOption {
+	return func(w *Writer) {
+		if n > 0 {
+			w.windowBytes = n
+		}
+	}
+}
+
+func WithWriterMetrics(m Metrics) WriterOption {
+	return func(w *Writer) { w.metrics = m }
+}
+
+// WithWriterResumeOffset seeds the writer's starting offset, e.g. after a
+// reconnect replays the receiver's last ack.
+func WithWriterResumeOffset(offset int64) WriterOption {
+	return func(w *Writer) {
+		w.offset = offset
+		w.acked = offset
+	}
+}
+
+func NewWriter(send SendFunc, agentID, reqID string, opts ...WriterOption) *Writer {
+	w := &Writer{
+		send:        send,
+		agentID:     agentID,
+		reqID:       reqID,
+		windowBytes: DefaultWindowBytes,
+	}
+	w.cond = sync.NewCond(&w.mu)
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Write blocks until the in-flight (unacked) byte count has room for data,
+// then sends it as a TypeChunk envelope at the writer's current offset. A
+// chunk larger than the window is sent whole once nothing is in flight, so
+// the unacked count can then exceed WindowBytes by that one chunk.
+func (w *Writer) Write(data []byte, channel string, eof bool) error {
+	w.mu.Lock()
+	for {
+		inflight := w.offset - w.acked
+		if inflight == 0 || inflight+int64(len(data)) <= w.windowBytes || w.closed {
+			break
+		}
+		w.cond.Wait()
+	}
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	offset := w.offset
+	w.offset += int64(len(data))
+	w.mu.Unlock()
+
+	env := protocol.Envelope{
+		V:       1,
+		Type:    protocol.TypeChunk,
+		AgentID: w.agentID,
+		ReqID:   w.reqID,
+		Ts:      time.Now().Unix(),
+		Payload: mustMarshal(protocol.ChunkPayload{
+			Channel: channel,
+			Data:    string(data),
+			Offset:  offset,
+			EOF:     eof,
+		}),
+	}
+	if err := w.send(env); err != nil {
+		return err
+	}
+	w.metrics.chunkSent(len(data))
+	return nil
+}
+
+// HandleAck applies a TypeChunkAck received from the peer, advancing the
+// acked offset and waking any Write blocked on window space.
+func (w *Writer) HandleAck(ack protocol.ChunkAckPayload) {
+	w.mu.Lock()
+	if ack.Offset > w.acked {
+		w.acked = ack.Offset
+	}
+	if ack.WindowBytes > 0 {
+		w.windowBytes = ack.WindowBytes
+	}
+	w.mu.Unlock()
+	w.cond.Broadcast()
+	w.metrics.chunkAcked(ack.Offset)
+}
+
+// Close unblocks any pending Write and marks the writer as done.
+func (w *Writer) Close() {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+	w.cond.Broadcast()
+}
+
+func mustMarshal(v any) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
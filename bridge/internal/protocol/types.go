@@ -9,8 +9,83 @@ const (
 	TypeInvoke    = "INVOKE"
 	TypeInvokeAck = "INVOKE_ACK"
 	TypeChunk     = "CHUNK"
+	TypeChunkAck  = "CHUNK_ACK"
 	TypeResult    = "RESULT"
 	TypeResultAck = "RESULT_ACK"
 	TypeCancel    = "CANCEL"
 	TypeCancelAck = "CANCEL_ACK"
+
+	// TypeConfigReload notifies a connected agent that the gateway's config
+	// was changed and should be re-fetched/re-applied.
+	TypeConfigReload = "CONFIG_RELOAD"
+
+	// TypeError carries a structured error, e.g. a failed HELLO handshake.
+	TypeError = "ERROR"
+
+	// TypePolicyDeny is published (not sent to the agent) when
+	// internal/policy denies an invoke, for audit via the events feed.
+	TypePolicyDeny = "POLICY_DENY"
+)
+
+// Feature names exchanged in HelloPayload.Features. Handlers gate optional
+// behavior on whether a feature was negotiated for the session (see
+// Envelope.ValidateFeatures).
+const (
+	FeatureCancel       = "cancel"
+	FeatureInvokeAck    = "invoke_ack"
+	FeatureResultAck    = "result_ack"
+	FeaturePingPong     = "ping_pong"
+	FeatureChunkedTools = "chunked_tools"
+	FeatureHMACV1       = "hmac_v1"
 )
+
+// MinSupportedV and MaxSupportedV are the envelope protocol version range
+// this build understands. HELLO negotiates the highest v both sides share.
+const (
+	MinSupportedV = 1
+	MaxSupportedV = 1
+)
+
+// typeFeatureRequirement maps an envelope Type to the feature name that must
+// be in a session's negotiated set for that type to be sent or accepted.
+// Types not listed here have no feature requirement.
+var typeFeatureRequirement = map[string]string{
+	TypeCancel:    FeatureCancel,
+	TypeCancelAck: FeatureCancel,
+	TypeInvokeAck: FeatureInvokeAck,
+	TypeResultAck: FeatureResultAck,
+	TypePing:      FeaturePingPong,
+	TypePong:      FeaturePingPong,
+}
+
+// NegotiateVersion returns the highest v in [minA,maxA] ∩ [minB,maxB], or
+// (0, false) if the ranges don't overlap.
+func NegotiateVersion(minA, maxA, minB, maxB int) (int, bool) {
+	lo, hi := minA, maxA
+	if minB > lo {
+		lo = minB
+	}
+	if maxB < hi {
+		hi = maxB
+	}
+	if lo > hi {
+		return 0, false
+	}
+	return hi, true
+}
+
+// IntersectFeatures returns the features present in both a and b, preserving
+// a's order.
+func IntersectFeatures(a, b []string) []string {
+	bSet := make(map[string]struct{}, len(b))
+	for _, f := range b {
+		bSet[f] = struct{}{}
+	}
+	out := make([]string, 0, len(a))
+	for _, f := range a {
+		if _, ok := bSet[f]; ok {
+			out = append(out, f)
+		}
+	}
+	return out
+}